@@ -0,0 +1,241 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"math"
+
+	"github.com/uestcer/utils/errors"
+)
+
+// Create a new empty directed graph.
+func NewGraph[K comparable, W any]() *Graph[K, W] {
+	return &Graph[K, W]{edges: make(map[K][]graphEdge[K, W])}
+}
+
+// A directed graph with vertices of type K and edge weights of type W.
+// Vertices and neighbors are iterated in insertion order.
+// Graph is not thread safe.
+type Graph[K comparable, W any] struct {
+	vertices []K
+	edges    map[K][]graphEdge[K, W]
+}
+
+type graphEdge[K comparable, W any] struct {
+	to     K
+	weight W
+}
+
+// Adds a vertex, does nothing if it exists.
+func (g *Graph[K, W]) AddVertex(id K) {
+	if _, ok := g.edges[id]; !ok {
+		g.vertices = append(g.vertices, id)
+		g.edges[id] = nil
+	}
+}
+
+// Adds an edge, and the vertices if they don't exist.
+// Replaces the weight if the edge exists.
+func (g *Graph[K, W]) AddEdge(from, to K, weight W) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	for i, e := range g.edges[from] {
+		if e.to == to {
+			g.edges[from][i].weight = weight
+			return
+		}
+	}
+	g.edges[from] = append(g.edges[from], graphEdge[K, W]{to, weight})
+}
+
+// Removes a vertex and all edges from or to it.
+func (g *Graph[K, W]) RemoveVertex(id K) {
+	if _, ok := g.edges[id]; !ok {
+		return
+	}
+	delete(g.edges, id)
+	for i, v := range g.vertices {
+		if v == id {
+			g.vertices = append(g.vertices[:i], g.vertices[i+1:]...)
+			break
+		}
+	}
+	for _, v := range g.vertices {
+		g.RemoveEdge(v, id)
+	}
+}
+
+// Removes the edge from one vertex to another, if it exists.
+func (g *Graph[K, W]) RemoveEdge(from, to K) {
+	edges := g.edges[from]
+	for i, e := range edges {
+		if e.to == to {
+			g.edges[from] = append(edges[:i], edges[i+1:]...)
+			return
+		}
+	}
+}
+
+// Returns the vertices the edges from id lead to.
+func (g *Graph[K, W]) Neighbors(id K) []K {
+	edges := g.edges[id]
+	result := make([]K, len(edges))
+	for i, e := range edges {
+		result[i] = e.to
+	}
+	return result
+}
+
+// Visits the vertices reachable from start in breadth-first order,
+// stops if visit returns false.
+func (g *Graph[K, W]) BFS(start K, visit func(K) bool) {
+	if _, ok := g.edges[start]; !ok {
+		return
+	}
+
+	visited := map[K]bool{start: true}
+	queue := []K{start}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		if !visit(v) {
+			return
+		}
+		for _, e := range g.edges[v] {
+			if !visited[e.to] {
+				visited[e.to] = true
+				queue = append(queue, e.to)
+			}
+		}
+	}
+}
+
+// Visits the vertices reachable from start in depth-first order,
+// stops if visit returns false.
+func (g *Graph[K, W]) DFS(start K, visit func(K) bool) {
+	if _, ok := g.edges[start]; !ok {
+		return
+	}
+	g.dfs(start, map[K]bool{}, visit)
+}
+
+func (g *Graph[K, W]) dfs(v K, visited map[K]bool, visit func(K) bool) bool {
+	visited[v] = true
+	if !visit(v) {
+		return false
+	}
+	for _, e := range g.edges[v] {
+		if !visited[e.to] && !g.dfs(e.to, visited, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the graph has a directed cycle.
+func (g *Graph[K, W]) HasCycle() bool {
+	_, err := g.TopologicalSort()
+	return err != nil
+}
+
+// Returns the vertices ordered so that every edge leads from an earlier
+// vertex to a later one. Returns an error if the graph has a cycle.
+func (g *Graph[K, W]) TopologicalSort() ([]K, error) {
+	inDegree := make(map[K]int, len(g.vertices))
+	for _, v := range g.vertices {
+		for _, e := range g.edges[v] {
+			inDegree[e.to]++
+		}
+	}
+
+	queue := []K{}
+	for _, v := range g.vertices {
+		if inDegree[v] == 0 {
+			queue = append(queue, v)
+		}
+	}
+
+	result := make([]K, 0, len(g.vertices))
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		result = append(result, v)
+		for _, e := range g.edges[v] {
+			inDegree[e.to]--
+			if inDegree[e.to] == 0 {
+				queue = append(queue, e.to)
+			}
+		}
+	}
+
+	if len(result) != len(g.vertices) {
+		return nil, errors.New("utils/collection: graph has a cycle")
+	}
+	return result, nil
+}
+
+// Returns the path with the least total weight from start to end, and its
+// weight, using Dijkstra's algorithm. weight converts an edge weight to a
+// non-negative distance. Returns an error if a vertex does not exist, end
+// is not reachable, or a distance is negative.
+func (g *Graph[K, W]) ShortestPath(start, end K, weight func(W) float64) ([]K, float64, error) {
+	if _, ok := g.edges[start]; !ok {
+		return nil, 0, errors.Newf("utils/collection: vertex %v does not exist", start)
+	}
+	if _, ok := g.edges[end]; !ok {
+		return nil, 0, errors.Newf("utils/collection: vertex %v does not exist", end)
+	}
+
+	type item struct {
+		v    K
+		dist float64
+	}
+	dist := map[K]float64{start: 0}
+	prev := map[K]K{}
+	done := map[K]bool{}
+	h := NewMinHeap(func(a, b item) bool { return a.dist < b.dist })
+	h.Push(item{start, 0})
+
+	for h.Len() > 0 {
+		x, _ := h.Pop()
+		if done[x.v] {
+			continue
+		}
+		done[x.v] = true
+		if x.v == end {
+			break
+		}
+
+		for _, e := range g.edges[x.v] {
+			w := weight(e.weight)
+			if w < 0 {
+				return nil, 0, errors.Newf("utils/collection: edge %v -> %v has negative weight %v", x.v, e.to, w)
+			}
+			d, ok := dist[e.to]
+			if !ok {
+				d = math.Inf(1)
+			}
+			if x.dist+w < d {
+				dist[e.to] = x.dist + w
+				prev[e.to] = x.v
+				h.Push(item{e.to, x.dist + w})
+			}
+		}
+	}
+
+	if !done[end] {
+		return nil, 0, errors.Newf("utils/collection: vertex %v is not reachable from %v", end, start)
+	}
+
+	path := []K{end}
+	for v := end; v != start; {
+		v = prev[v]
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path, dist[end], nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func newTestGraph() *Graph[string, int] {
+	g := NewGraph[string, int]()
+	g.AddEdge("a", "b", 1)
+	g.AddEdge("a", "c", 4)
+	g.AddEdge("b", "c", 2)
+	g.AddEdge("b", "d", 5)
+	g.AddEdge("c", "d", 1)
+	return g
+}
+
+func identity(w int) float64 {
+	return float64(w)
+}
+
+func TestGraphEdges(t *testing.T) {
+	g := newTestGraph()
+	if !reflect.DeepEqual(g.Neighbors("a"), []string{"b", "c"}) ||
+		len(g.Neighbors("d")) != 0 || len(g.Neighbors("x")) != 0 {
+		t.Fatal()
+	}
+
+	g.RemoveEdge("a", "b")
+	if !reflect.DeepEqual(g.Neighbors("a"), []string{"c"}) {
+		t.Fatal()
+	}
+
+	g.RemoveVertex("c")
+	if len(g.Neighbors("a")) != 0 || !reflect.DeepEqual(g.Neighbors("b"), []string{"d"}) {
+		t.Fatal()
+	}
+}
+
+func TestGraphBFS(t *testing.T) {
+	g := newTestGraph()
+	visited := []string{}
+	g.BFS("a", func(v string) bool {
+		visited = append(visited, v)
+		return true
+	})
+	if !reflect.DeepEqual(visited, []string{"a", "b", "c", "d"}) {
+		t.Fatal(visited)
+	}
+
+	visited = []string{}
+	g.BFS("a", func(v string) bool {
+		visited = append(visited, v)
+		return v != "b"
+	})
+	if !reflect.DeepEqual(visited, []string{"a", "b"}) {
+		t.Fatal(visited)
+	}
+}
+
+func TestGraphDFS(t *testing.T) {
+	g := newTestGraph()
+	visited := []string{}
+	g.DFS("a", func(v string) bool {
+		visited = append(visited, v)
+		return true
+	})
+	if !reflect.DeepEqual(visited, []string{"a", "b", "c", "d"}) {
+		t.Fatal(visited)
+	}
+
+	visited = []string{}
+	g.DFS("b", func(v string) bool {
+		visited = append(visited, v)
+		return v != "c"
+	})
+	if !reflect.DeepEqual(visited, []string{"b", "c"}) {
+		t.Fatal(visited)
+	}
+}
+
+func TestGraphTopologicalSort(t *testing.T) {
+	g := newTestGraph()
+	order, err := g.TopologicalSort()
+	if err != nil || !reflect.DeepEqual(order, []string{"a", "b", "c", "d"}) {
+		t.Fatal(order)
+	}
+	if g.HasCycle() {
+		t.Fatal()
+	}
+
+	g.AddEdge("d", "a", 1)
+	if _, err := g.TopologicalSort(); err == nil || !g.HasCycle() {
+		t.Fatal()
+	}
+}
+
+func TestGraphShortestPath(t *testing.T) {
+	g := newTestGraph()
+	path, dist, err := g.ShortestPath("a", "d", identity)
+	if err != nil || dist != 4 || !reflect.DeepEqual(path, []string{"a", "b", "c", "d"}) {
+		t.Fatal(path, dist, err)
+	}
+
+	path, dist, err = g.ShortestPath("a", "a", identity)
+	if err != nil || dist != 0 || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Fatal()
+	}
+
+	if _, _, err := g.ShortestPath("d", "a", identity); err == nil {
+		t.Fatal()
+	}
+	if _, _, err := g.ShortestPath("a", "x", identity); err == nil {
+		t.Fatal()
+	}
+
+	g.AddEdge("a", "b", -1)
+	if _, _, err := g.ShortestPath("a", "d", identity); err == nil {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,138 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"container/heap"
+)
+
+// Create a new empty heap, Pop returns the smallest element by less.
+func NewMinHeap[T any](less func(a, b T) bool) *MinHeap[T] {
+	return &MinHeap[T]{heapSlice[T]{less: less}}
+}
+
+// Create a new empty heap, Pop returns the largest element by less.
+func NewMaxHeap[T any](less func(a, b T) bool) *MaxHeap[T] {
+	return &MaxHeap[T]{heapSlice[T]{less: func(a, b T) bool {
+		return less(b, a)
+	}}}
+}
+
+// A priority queue returning the smallest element first.
+// MinHeap is not thread safe.
+type MinHeap[T any] struct {
+	h heapSlice[T]
+}
+
+// Adds v to the heap, in O(log n).
+func (h *MinHeap[T]) Push(v T) {
+	heap.Push(&h.h, v)
+}
+
+// Removes and returns the smallest element, in O(log n).
+// Returns false if the heap is empty.
+func (h *MinHeap[T]) Pop() (T, bool) {
+	return h.h.pop()
+}
+
+// Returns the smallest element without removing it.
+// Returns false if the heap is empty.
+func (h *MinHeap[T]) Peek() (T, bool) {
+	return h.h.peek()
+}
+
+// Returns the number of elements in the heap.
+func (h *MinHeap[T]) Len() int {
+	return h.h.Len()
+}
+
+// A priority queue returning the largest element first.
+// MaxHeap is not thread safe.
+type MaxHeap[T any] struct {
+	h heapSlice[T]
+}
+
+// Adds v to the heap, in O(log n).
+func (h *MaxHeap[T]) Push(v T) {
+	heap.Push(&h.h, v)
+}
+
+// Removes and returns the largest element, in O(log n).
+// Returns false if the heap is empty.
+func (h *MaxHeap[T]) Pop() (T, bool) {
+	return h.h.pop()
+}
+
+// Returns the largest element without removing it.
+// Returns false if the heap is empty.
+func (h *MaxHeap[T]) Peek() (T, bool) {
+	return h.h.peek()
+}
+
+// Returns the number of elements in the heap.
+func (h *MaxHeap[T]) Len() int {
+	return h.h.Len()
+}
+
+// Returns a new slice with the elements of s sorted by less, in O(n log n).
+// s is not modified.
+func HeapSort[T any](s []T, less func(a, b T) bool) []T {
+	h := heapSlice[T]{less: less, elements: make([]T, len(s))}
+	copy(h.elements, s)
+	heap.Init(&h)
+
+	result := make([]T, len(s))
+	for i := range result {
+		result[i], _ = h.pop()
+	}
+	return result
+}
+
+// Implements heap.Interface, the smallest element by less is at the root.
+type heapSlice[T any] struct {
+	less     func(a, b T) bool
+	elements []T
+}
+
+func (h *heapSlice[T]) Len() int {
+	return len(h.elements)
+}
+
+func (h *heapSlice[T]) Less(i, j int) bool {
+	return h.less(h.elements[i], h.elements[j])
+}
+
+func (h *heapSlice[T]) Swap(i, j int) {
+	h.elements[i], h.elements[j] = h.elements[j], h.elements[i]
+}
+
+func (h *heapSlice[T]) Push(x interface{}) {
+	h.elements = append(h.elements, x.(T))
+}
+
+func (h *heapSlice[T]) Pop() interface{} {
+	n := len(h.elements) - 1
+	v := h.elements[n]
+	var zero T
+	h.elements[n] = zero
+	h.elements = h.elements[:n]
+	return v
+}
+
+func (h *heapSlice[T]) pop() (T, bool) {
+	if len(h.elements) == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(h).(T), true
+}
+
+func (h *heapSlice[T]) peek() (T, bool) {
+	if len(h.elements) == 0 {
+		var zero T
+		return zero, false
+	}
+	return h.elements[0], true
+}
@@ -0,0 +1,87 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func lessInt(a, b int) bool {
+	return a < b
+}
+
+func TestMinHeap(t *testing.T) {
+	h := NewMinHeap(lessInt)
+	if _, ok := h.Pop(); ok {
+		t.Fatal()
+	}
+	if _, ok := h.Peek(); ok {
+		t.Fatal()
+	}
+
+	for _, v := range []int{5, 1, 4, 2, 3} {
+		h.Push(v)
+	}
+	if v, ok := h.Peek(); !ok || v != 1 || h.Len() != 5 {
+		t.Fatal()
+	}
+
+	values := []int{}
+	for h.Len() > 0 {
+		v, _ := h.Pop()
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []int{1, 2, 3, 4, 5}) {
+		t.Fatal()
+	}
+}
+
+func TestMaxHeap(t *testing.T) {
+	h := NewMaxHeap(func(a, b string) bool { return a < b })
+	for _, v := range []string{"b", "c", "a"} {
+		h.Push(v)
+	}
+	if v, ok := h.Peek(); !ok || v != "c" {
+		t.Fatal()
+	}
+
+	values := []string{}
+	for v, ok := h.Pop(); ok; v, ok = h.Pop() {
+		values = append(values, v)
+	}
+	if !reflect.DeepEqual(values, []string{"c", "b", "a"}) {
+		t.Fatal()
+	}
+}
+
+func TestHeapSort(t *testing.T) {
+	s := rand.New(rand.NewSource(1)).Perm(100)
+	r := HeapSort(s, lessInt)
+
+	expected := append([]int(nil), s...)
+	sort.Ints(expected)
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatal()
+	}
+
+	if len(HeapSort([]int{}, lessInt)) != 0 {
+		t.Fatal()
+	}
+}
+
+func benchmarkHeapSort(b *testing.B, n int) {
+	s := rand.New(rand.NewSource(1)).Perm(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		HeapSort(s, lessInt)
+	}
+}
+
+func BenchmarkHeapSort1K(b *testing.B)   { benchmarkHeapSort(b, 1000) }
+func BenchmarkHeapSort10K(b *testing.B)  { benchmarkHeapSort(b, 10000) }
+func BenchmarkHeapSort100K(b *testing.B) { benchmarkHeapSort(b, 100000) }
@@ -4,6 +4,14 @@
 
 package collection
 
+import (
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sort"
+	"strings"
+)
+
 // Create a new set with elements.
 func NewSet(elements ...interface{}) Set {
 	set := &baseSet{make(map[interface{}]bool)}
@@ -13,6 +21,109 @@ func NewSet(elements ...interface{}) Set {
 	return set
 }
 
+// Create a new set with the elements of all given sets.
+// The given sets are not modified, nil sets are ignored.
+func MergeSets(sets ...Set) Set {
+	set := NewSet()
+	set.Merge(sets...)
+	return set
+}
+
+// Removes all of the elements from dst, and adds all elements in src, e.g.
+// to reuse a set in a hot loop instead of cloning. The sets of this package
+// reuse the storage of dst. Nil src results in an empty set.
+func CopyFrom(dst, src Set) {
+	if c, ok := dst.(interface{ CopyFrom(Set) }); ok {
+		c.CopyFrom(src)
+		return
+	}
+	if dst == src {
+		return
+	}
+	dst.Clear()
+	dst.Union(src)
+}
+
+// Fills out with all of the elements in s, in the order of Foreach. out must
+// be a pointer to a typed slice, e.g. *[]int. The slice is replaced, not
+// appended to.
+// NOTE: Panic if out is not a slice pointer, or an element is not
+// assignable to the slice element type.
+func ToTypedSlice(s Set, out interface{}) {
+	typedSlice(s, out)
+}
+
+// Returns the number of elements in both a and b, without building the
+// intersection, e.g. for the Jaccard similarity. A nil set is empty.
+func IntersectSize(a, b Set) int {
+	return intersectSize(a, b)
+}
+
+// Returns the number of elements in a or b, without building the union.
+// A nil set is empty.
+func UnionSize(a, b Set) int {
+	return unionSize(a, b)
+}
+
+// Iterate the elements of s in the order given by less, and invoke f by
+// every element. It gives a reproducible order without changing s.
+// A nil set is empty.
+func ForeachSorted(s Set, less func(a, b interface{}) bool, f func(interface{})) {
+	if fs, ok := s.(interface {
+		ForeachSorted(func(a, b interface{}) bool, func(interface{}))
+	}); ok {
+		fs.ForeachSorted(less, f)
+		return
+	}
+	if s == nil {
+		return
+	}
+	for _, v := range sortedElements(s, less) {
+		f(v)
+	}
+}
+
+// Create two new sets in a single pass over s, one with all elements
+// satisfied f, the other with the rest. A SortedSet is split into two
+// SortedSets. A nil set is empty.
+func Partition(s Set, f func(interface{}) bool) (matched, unmatched Set) {
+	if p, ok := s.(interface {
+		Partition(func(interface{}) bool) (Set, Set)
+	}); ok {
+		return p.Partition(f)
+	}
+	matched, unmatched = NewSet(), NewSet()
+	if s == nil {
+		return
+	}
+	s.Foreach(func(v interface{}) {
+		if f(v) {
+			matched.Add(v)
+		} else {
+			unmatched.Add(v)
+		}
+	})
+	return
+}
+
+// Returns an order-independent hash of the elements of s.
+// Equal sets have the same hash, so it can be used as a map key
+// for the set contents. Elements are hashed by their type and
+// fmt "%v" form, which must be stable for the hash to be stable.
+// A nil set is empty, its hash is 0.
+func Hash(s Set) uint64 {
+	if h, ok := s.(interface{ Hash() uint64 }); ok {
+		return h.Hash()
+	}
+	var h uint64
+	if s != nil {
+		s.Foreach(func(v interface{}) {
+			h ^= hashElement(v)
+		})
+	}
+	return h
+}
+
 // A collection that contains no duplicate elements.
 // Set is not thread safe.
 type Set interface {
@@ -26,14 +137,34 @@ type Set interface {
 	// Returns true if this set contains the specified element.
 	Contains(v interface{}) bool
 
+	// Returns true if this set contains all the specified elements,
+	// true if no element is specified.
+	ContainsAll(elements ...interface{}) bool
+
+	// Returns true if this set contains any of the specified elements,
+	// false if no element is specified.
+	ContainsAny(elements ...interface{}) bool
+
 	// Returns an slice containing all of the elements in this set.
 	// The caller is free to modify the returned array.
 	ToSlice() []interface{}
 
+	// Returns an slice containing all of the elements in this set,
+	// sorted by less.
+	ToSortedSlice(less func(a, b interface{}) bool) []interface{}
+
+	// Returns a sorted slice containing all of the elements in this set.
+	// NOTE: Panic if an element type is not string.
+	ToSortedStringSlice() []string
+
 	// Adds the specified element to this set
 	// Return true, if this set already contain the specified element
 	Add(v interface{}) bool
 
+	// Adds all values to this set, and returns this set for chaining,
+	// e.g. NewSet().With(1, 2).With(3)
+	With(values ...interface{}) Set
+
 	// Removes the specified element from this set
 	// Return true, if this set contained the specified element
 	Remove(v interface{}) bool
@@ -44,6 +175,9 @@ type Set interface {
 	// Adds all elements in s into this set.
 	Union(s Set)
 
+	// Adds all elements in every given set into this set.
+	Merge(sets ...Set)
+
 	// Removes all elements not in s from this set.
 	Intersect(s Set)
 
@@ -67,6 +201,60 @@ type Set interface {
 
 	// Create a new set with all elements satisfied f.
 	Filter(f func(interface{}) bool) Set
+
+	// Aggregate the elements into a single value, by calling f with the
+	// accumulated value (initial at first) and every element.
+	// The iteration order is undefined, same as Foreach.
+	Reduce(initial interface{}, f func(acc, element interface{}) interface{}) interface{}
+
+	// Returns the elements formatted by fmt "%v" in braces, e.g. "{1, 2, 3}".
+	// Numbers come first in numeric order, then the other elements
+	// in the order of their formatted text. A SortedSet keeps its order.
+	String() string
+}
+
+// Returns an iterator over a snapshot of the elements of s taken now, in the
+// same order as Foreach, e.g. ascending for a SortedSet. Later changes to s
+// are not visible. A nil set is empty.
+func NewIterator(s Set) Iterator {
+	if it, ok := s.(interface{ Iterator() Iterator }); ok {
+		return it.Iterator()
+	}
+	if s == nil {
+		return &sliceIterator{}
+	}
+	return &sliceIterator{elements: s.ToSlice()}
+}
+
+// Pull-style iteration over the elements of a collection, e.g. to advance
+// several iterators together in a merge.
+type Iterator interface {
+
+	// Returns true if the iteration has more elements.
+	HasNext() bool
+
+	// Returns the next element.
+	// NOTE: Panic if the iteration has no more elements.
+	Next() interface{}
+}
+
+// An Iterator over a slice.
+type sliceIterator struct {
+	elements []interface{}
+	next     int
+}
+
+func (it *sliceIterator) HasNext() bool {
+	return it.next < len(it.elements)
+}
+
+func (it *sliceIterator) Next() interface{} {
+	if !it.HasNext() {
+		panic("utils/collection: iterator has no next element.")
+	}
+	v := it.elements[it.next]
+	it.next++
+	return v
 }
 
 type baseSet struct {
@@ -86,6 +274,24 @@ func (s *baseSet) Contains(v interface{}) bool {
 	return ok
 }
 
+func (s *baseSet) ContainsAll(elements ...interface{}) bool {
+	for _, v := range elements {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *baseSet) ContainsAny(elements ...interface{}) bool {
+	for _, v := range elements {
+		if s.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *baseSet) ToSlice() []interface{} {
 	values := make([]interface{}, s.Size())
 	i := 0
@@ -96,12 +302,31 @@ func (s *baseSet) ToSlice() []interface{} {
 	return values
 }
 
+func (s *baseSet) ToSortedSlice(less func(a, b interface{}) bool) []interface{} {
+	return sortedElements(s, less)
+}
+
+func (s *baseSet) ToSortedStringSlice() []string {
+	return sortedStrings(s)
+}
+
+func (s *baseSet) ToTypedSlice(out interface{}) {
+	typedSlice(s, out)
+}
+
 func (s *baseSet) Add(v interface{}) bool {
 	_, ok := s.elements[v]
 	s.elements[v] = true
 	return ok
 }
 
+func (s *baseSet) With(values ...interface{}) Set {
+	for _, v := range values {
+		s.elements[v] = true
+	}
+	return s
+}
+
 func (s *baseSet) Remove(v interface{}) bool {
 	_, ok := s.elements[v]
 	if ok {
@@ -114,6 +339,16 @@ func (s *baseSet) Clear() {
 	s.elements = make(map[interface{}]bool)
 }
 
+func (s0 *baseSet) CopyFrom(s1 Set) {
+	if s1 == Set(s0) {
+		return
+	}
+	for k := range s0.elements {
+		delete(s0.elements, k)
+	}
+	s0.Union(s1)
+}
+
 func (s0 *baseSet) Union(s1 Set) {
 	if s1 == nil {
 		return
@@ -123,6 +358,12 @@ func (s0 *baseSet) Union(s1 Set) {
 	})
 }
 
+func (s *baseSet) Merge(sets ...Set) {
+	for _, s1 := range sets {
+		s.Union(s1)
+	}
+}
+
 func (s *baseSet) Intersect(s1 Set) {
 	if s1 == nil {
 		return
@@ -144,6 +385,14 @@ func (s *baseSet) Subtract(s1 Set) {
 	})
 }
 
+func (s *baseSet) IntersectSize(s1 Set) int {
+	return intersectSize(s, s1)
+}
+
+func (s *baseSet) UnionSize(s1 Set) int {
+	return unionSize(s, s1)
+}
+
 func (s *baseSet) IsSubset(s1 Set) bool {
 	if s1 == nil || s.Size() > s1.Size() {
 		return false
@@ -184,6 +433,16 @@ func (s *baseSet) Foreach(f func(interface{})) {
 	}
 }
 
+func (s *baseSet) Iterator() Iterator {
+	return &sliceIterator{elements: s.ToSlice()}
+}
+
+func (s *baseSet) ForeachSorted(less func(a, b interface{}) bool, f func(interface{})) {
+	for _, v := range sortedElements(s, less) {
+		f(v)
+	}
+}
+
 func (s *baseSet) Map(f func(interface{}) interface{}) Set {
 	result := NewSet()
 	for k, _ := range s.elements {
@@ -201,3 +460,163 @@ func (s *baseSet) Filter(f func(interface{}) bool) Set {
 	}
 	return result
 }
+
+func (s *baseSet) Partition(f func(interface{}) bool) (matched, unmatched Set) {
+	matched, unmatched = NewSet(), NewSet()
+	for k := range s.elements {
+		if f(k) {
+			matched.Add(k)
+		} else {
+			unmatched.Add(k)
+		}
+	}
+	return
+}
+
+func (s *baseSet) Reduce(initial interface{}, f func(acc, element interface{}) interface{}) interface{} {
+	acc := initial
+	for k := range s.elements {
+		acc = f(acc, k)
+	}
+	return acc
+}
+
+func (s *baseSet) String() string {
+	return formatElements(sortedElements(s, fmtLess))
+}
+
+func (s *baseSet) Hash() uint64 {
+	var h uint64
+	for k := range s.elements {
+		h ^= hashElement(k)
+	}
+	return h
+}
+
+// Returns a snapshot of the elements of s sorted by less.
+func sortedElements(s Set, less func(a, b interface{}) bool) []interface{} {
+	values := s.ToSlice()
+	sort.Slice(values, func(i, j int) bool {
+		return less(values[i], values[j])
+	})
+	return values
+}
+
+// Returns the elements of s as a sorted string slice.
+// NOTE: Panic if an element type is not string.
+func sortedStrings(s Set) []string {
+	values := make([]string, 0, s.Size())
+	s.Foreach(func(v interface{}) {
+		str, ok := v.(string)
+		if !ok {
+			panic(fmt.Sprintf("utils/collection: element type is not string, %T.", v))
+		}
+		values = append(values, str)
+	})
+	sort.Strings(values)
+	return values
+}
+
+// Fill the slice pointed by out with the elements of s, in the order of Foreach.
+func typedSlice(s Set, out interface{}) {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Slice {
+		panic(fmt.Sprintf("utils/collection: argument type is not slice pointer, %T.", out))
+	}
+
+	t := v.Elem().Type().Elem()
+	result := reflect.MakeSlice(v.Elem().Type(), 0, s.Size())
+	s.Foreach(func(e interface{}) {
+		ev := reflect.ValueOf(e)
+		switch {
+		case ev.IsValid() && ev.Type().AssignableTo(t):
+		case !ev.IsValid() && nillable(t):
+			ev = reflect.Zero(t)
+		default:
+			panic(fmt.Sprintf("utils/collection: element type %T is not assignable to %s.", e, t))
+		}
+		result = reflect.Append(result, ev)
+	})
+	v.Elem().Set(result)
+}
+
+// Check if nil is assignable to type t.
+func nillable(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map, reflect.Chan, reflect.Func:
+		return true
+	}
+	return false
+}
+
+// Count the common elements, iterate the smaller set and probe the larger.
+func intersectSize(s0, s1 Set) int {
+	if s0 == nil || s1 == nil {
+		return 0
+	}
+	if s0.Size() > s1.Size() {
+		s0, s1 = s1, s0
+	}
+
+	n := 0
+	s0.Foreach(func(v interface{}) {
+		if s1.Contains(v) {
+			n++
+		}
+	})
+	return n
+}
+
+func unionSize(s0, s1 Set) int {
+	n := 0
+	if s0 != nil {
+		n += s0.Size()
+	}
+	if s1 != nil {
+		n += s1.Size()
+	}
+	return n - intersectSize(s0, s1)
+}
+
+// Format the elements by fmt "%v" in braces, e.g. "{1, 2, 3}".
+func formatElements(elements []interface{}) string {
+	strs := make([]string, len(elements))
+	for i, v := range elements {
+		strs[i] = fmt.Sprintf("%v", v)
+	}
+	return "{" + strings.Join(strs, ", ") + "}"
+}
+
+// Order numbers first by value, then the other elements by fmt "%v" text.
+func fmtLess(a, b interface{}) bool {
+	x, xok := toFloat(a)
+	y, yok := toFloat(b)
+	switch {
+	case xok && yok && x != y:
+		return x < y
+	case xok != yok:
+		return xok
+	}
+	return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+}
+
+// Convert a number of a built-in numeric kind to float64.
+func toFloat(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	}
+	return 0, false
+}
+
+// Hash an element by its type and string form.
+func hashElement(v interface{}) uint64 {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%T:%v", v, v)
+	return h.Sum64()
+}
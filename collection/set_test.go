@@ -5,6 +5,9 @@
 package collection
 
 import (
+	"fmt"
+	"reflect"
+	"sort"
 	"testing"
 )
 
@@ -52,6 +55,32 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestContainsAll(t *testing.T) {
+	set1 := NewSet(1, "a", 2.5)
+	set2 := NewSet()
+
+	if !set1.ContainsAll(1, "a") || !set1.ContainsAll(1, "a", 2.5) ||
+		set1.ContainsAll(1, "1") || !set1.ContainsAll() {
+		t.Fatal()
+	}
+	if set2.ContainsAll(1) || !set2.ContainsAll() {
+		t.Fatal()
+	}
+}
+
+func TestContainsAny(t *testing.T) {
+	set1 := NewSet(1, "a", 2.5)
+	set2 := NewSet()
+
+	if !set1.ContainsAny("1", 2.5) || set1.ContainsAny("1", 2) ||
+		set1.ContainsAny() {
+		t.Fatal()
+	}
+	if set2.ContainsAny(1) || set2.ContainsAny() {
+		t.Fatal()
+	}
+}
+
 func TestToSlice(t *testing.T) {
 	set1 := NewSet(1, 2, 3)
 	set2 := NewSet()
@@ -64,6 +93,33 @@ func TestToSlice(t *testing.T) {
 	}
 }
 
+func TestToSortedSlice(t *testing.T) {
+	set1 := NewSet(3, 1, 2)
+	s := set1.ToSortedSlice(func(a, b interface{}) bool {
+		return a.(int) < b.(int)
+	})
+	if !reflect.DeepEqual(s, []interface{}{1, 2, 3}) {
+		t.Fatal()
+	}
+}
+
+func TestToSortedStringSlice(t *testing.T) {
+	set1 := NewSet("b", "c", "a")
+	if !reflect.DeepEqual(set1.ToSortedStringSlice(), []string{"a", "b", "c"}) {
+		t.Fatal()
+	}
+	if len(NewSet().ToSortedStringSlice()) != 0 {
+		t.Fatal()
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	NewSet("a", 1).ToSortedStringSlice()
+}
+
 func TestAdd(t *testing.T) {
 	set := NewSet()
 	exist := set.Add(1)
@@ -76,6 +132,16 @@ func TestAdd(t *testing.T) {
 	}
 }
 
+func TestWith(t *testing.T) {
+	set := NewSet(1)
+	if set.With(1, 2).With(3) != set || !set.IsEqual(NewSet(1, 2, 3)) {
+		t.Fatal(set.ToSlice())
+	}
+	if NewSet().With().Size() != 0 {
+		t.Fatal()
+	}
+}
+
 func TestRemove(t *testing.T) {
 	set := NewSet(1, 2, 3)
 	exist := set.Remove(1)
@@ -116,6 +182,32 @@ func TestUnion(t *testing.T) {
 	}
 }
 
+func TestMerge(t *testing.T) {
+	set1 := NewSet(1)
+	set1.Merge(NewSet(2, 3), nil, NewSortedSet(func(a, b interface{}) bool {
+		return a.(int) < b.(int)
+	}, 3, 4))
+	if !set1.IsEqual(NewSet(1, 2, 3, 4)) {
+		t.Fatal()
+	}
+}
+
+func TestMergeSets(t *testing.T) {
+	set1 := NewSet(1, 2)
+	set2 := NewSet(2, 3)
+	set3 := MergeSets(set1, nil, set2)
+	if !set3.IsEqual(NewSet(1, 2, 3)) {
+		t.Fatal()
+	}
+	if !set1.IsEqual(NewSet(1, 2)) || !set2.IsEqual(NewSet(2, 3)) {
+		t.Fatal()
+	}
+
+	if !MergeSets().IsEmpty() {
+		t.Fatal()
+	}
+}
+
 func TestIntersect(t *testing.T) {
 	set1 := NewSet(1, 2, 3)
 	set2 := NewSet(2, 3, 4)
@@ -179,6 +271,43 @@ func TestForeach(t *testing.T) {
 	}
 }
 
+func TestForeachSorted(t *testing.T) {
+	set1 := NewSet(3, 1, 4, 2)
+	values := []interface{}{}
+	less := func(a, b interface{}) bool {
+		return a.(int) < b.(int)
+	}
+	ForeachSorted(set1, less, func(i interface{}) {
+		values = append(values, i)
+	})
+	if !reflect.DeepEqual(values, []interface{}{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+
+	values = values[:0]
+	set1.(*baseSet).ForeachSorted(less, func(i interface{}) {
+		values = append(values, i)
+	})
+	if !reflect.DeepEqual(values, []interface{}{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+
+	values = values[:0]
+	ForeachSorted(wrappedSet{set1}, less, func(i interface{}) {
+		values = append(values, i)
+	})
+	if !reflect.DeepEqual(values, []interface{}{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+
+	calls := 0
+	ForeachSorted(NewSet(), less, func(i interface{}) { calls++ })
+	ForeachSorted(nil, less, func(i interface{}) { calls++ })
+	if calls != 0 {
+		t.Fatal()
+	}
+}
+
 func TestMap(t *testing.T) {
 	set1 := NewSet(1, 2, 3)
 	set2 := set1.Map(func(i interface{}) interface{} {
@@ -200,3 +329,216 @@ func TestFilter(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestPartition(t *testing.T) {
+	set1 := NewSet(1, 2, 3, 4, 5)
+	isEven := func(i interface{}) bool {
+		return i.(int)%2 == 0
+	}
+	even, odd := Partition(set1, isEven)
+	if !even.IsEqual(NewSet(2, 4)) || !odd.IsEqual(NewSet(1, 3, 5)) {
+		t.Fatal()
+	}
+	if e, o := set1.(*baseSet).Partition(isEven); !e.IsEqual(even) || !o.IsEqual(odd) {
+		t.Fatal()
+	}
+	if e, o := Partition(wrappedSet{set1}, isEven); !e.IsEqual(even) || !o.IsEqual(odd) {
+		t.Fatal()
+	}
+	if e, o := Partition(nil, isEven); !e.IsEmpty() || !o.IsEmpty() {
+		t.Fatal()
+	}
+
+	even.Union(odd)
+	if !even.IsEqual(set1) {
+		t.Fatal()
+	}
+}
+
+func TestReduce(t *testing.T) {
+	set1 := NewSet(1, 2, 3)
+	sum := set1.Reduce(0, func(acc, element interface{}) interface{} {
+		return acc.(int) + element.(int)
+	})
+	if sum != 6 {
+		t.Fatal()
+	}
+
+	if NewSet().Reduce("initial", nil) != "initial" {
+		t.Fatal()
+	}
+}
+
+func TestHash(t *testing.T) {
+	set1 := NewSet(1, 2, 3)
+	set2 := NewSet(3, 1, 2)
+	set3 := NewSet(1, 2)
+	set4 := NewSet("1", "2", "3")
+	if Hash(set1) != Hash(set2) ||
+		Hash(set1) == Hash(set3) ||
+		Hash(set1) == Hash(set4) {
+		t.Fatal()
+	}
+	if set1.(*baseSet).Hash() != Hash(set1) {
+		t.Fatal()
+	}
+
+	if Hash(NewSet()) != 0 || Hash(nil) != 0 {
+		t.Fatal()
+	}
+	if Hash(wrappedSet{set2}) != Hash(set1) {
+		t.Fatal()
+	}
+}
+
+func TestIntersectUnionSize(t *testing.T) {
+	set1 := NewSet(1, 2, 3, 4)
+	set2 := NewSet(3, 4, 5)
+	if IntersectSize(set1, set2) != 2 || IntersectSize(set2, set1) != 2 {
+		t.Fatal()
+	}
+	if UnionSize(set1, set2) != 5 || UnionSize(set2, set1) != 5 {
+		t.Fatal()
+	}
+	if IntersectSize(set1, nil) != 0 || UnionSize(set1, nil) != 4 || UnionSize(nil, set2) != 3 {
+		t.Fatal()
+	}
+	if IntersectSize(set1, NewSet()) != 0 || UnionSize(set1, NewSet()) != 4 {
+		t.Fatal()
+	}
+	if set1.(*baseSet).IntersectSize(set2) != 2 || set1.(*baseSet).UnionSize(set2) != 5 {
+		t.Fatal()
+	}
+}
+
+func TestToTypedSlice(t *testing.T) {
+	var ints []int
+	ToTypedSlice(NewSet(3, 1, 2), &ints)
+	sort.Ints(ints)
+	if !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Fatal(ints)
+	}
+
+	// The slice is replaced.
+	ToTypedSlice(NewSet(9), &ints)
+	if !reflect.DeepEqual(ints, []int{9}) {
+		t.Fatal(ints)
+	}
+
+	var values []interface{}
+	ToTypedSlice(NewSet(nil), &values)
+	if !reflect.DeepEqual(values, []interface{}{nil}) {
+		t.Fatal(values)
+	}
+
+	NewSet(4).(*baseSet).ToTypedSlice(&ints)
+	if !reflect.DeepEqual(ints, []int{4}) {
+		t.Fatal(ints)
+	}
+}
+
+func TestToTypedSlicePanic(t *testing.T) {
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/collection: argument type is not slice pointer, []int." {
+				t.Fatal(r)
+			}
+		}()
+		ToTypedSlice(NewSet(1), []int{})
+	}()
+
+	defer func() {
+		r := recover()
+		if r != "utils/collection: element type string is not assignable to int." {
+			t.Fatal(r)
+		}
+	}()
+	var ints []int
+	ToTypedSlice(NewSet("a"), &ints)
+}
+
+func TestCopyFrom(t *testing.T) {
+	set := NewSet(1, 2, 3)
+	CopyFrom(set, NewSet(3, 4))
+	if !set.IsEqual(NewSet(3, 4)) {
+		t.Fatal(set.ToSlice())
+	}
+
+	CopyFrom(set, set)
+	if !set.IsEqual(NewSet(3, 4)) {
+		t.Fatal(set.ToSlice())
+	}
+
+	CopyFrom(set, nil)
+	if !set.IsEmpty() {
+		t.Fatal(set.ToSlice())
+	}
+
+	// Sets outside this package are cleared and refilled.
+	other := wrappedSet{NewSet(1)}
+	CopyFrom(other, NewSet(2, 3))
+	if !other.IsEqual(NewSet(2, 3)) {
+		t.Fatal(other.ToSlice())
+	}
+	CopyFrom(other, other)
+	if !other.IsEqual(NewSet(2, 3)) {
+		t.Fatal(other.ToSlice())
+	}
+}
+
+// A Set implemented outside this package, without the optional methods.
+type wrappedSet struct {
+	Set
+}
+
+func TestString(t *testing.T) {
+	cases := []struct {
+		set  Set
+		want string
+	}{
+		{NewSet(), "{}"},
+		{NewSet(3, 1, 2), "{1, 2, 3}"},
+		{NewSet(10, 9, 100), "{9, 10, 100}"},
+		{NewSet("b", "a", "c"), "{a, b, c}"},
+		{NewSet("x", 2, 1.5, true), "{1.5, 2, true, x}"},
+	}
+	for _, c := range cases {
+		if s := c.set.String(); s != c.want {
+			t.Fatal(s, c.want)
+		}
+		if s := fmt.Sprint(c.set); s != c.want {
+			t.Fatal(s, c.want)
+		}
+	}
+}
+
+func TestIterator(t *testing.T) {
+	set := NewSet(1, 2, 3)
+	it := NewIterator(set)
+	set.Add(4)
+
+	got := NewSet()
+	for it.HasNext() {
+		got.Add(it.Next())
+	}
+	if !got.IsEqual(NewSet(1, 2, 3)) {
+		t.Fatal(got.ToSlice())
+	}
+	if NewIterator(wrappedSet{NewSet(1)}).Next() != 1 || NewIterator(nil).HasNext() {
+		t.Fatal()
+	}
+	if NewIterator(NewSet()).HasNext() {
+		t.Fatal()
+	}
+}
+
+func TestIteratorPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/collection: iterator has no next element." {
+			t.Fatal(r)
+		}
+	}()
+	NewIterator(NewSet()).Next()
+}
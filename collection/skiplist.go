@@ -0,0 +1,145 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"cmp"
+	"math/rand"
+)
+
+// The max number of levels of a skip list.
+const skipListMaxLevel = 32
+
+// Create a new empty skip list.
+func NewSkipList[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return &SkipList[K, V]{
+		head:  &skipListNode[K, V]{next: make([]*skipListNode[K, V], skipListMaxLevel)},
+		level: 1,
+	}
+}
+
+// A sorted key-value store based on a probabilistic skip list.
+// Lookups, insertions and deletions take O(log n) expected time,
+// and keys are iterated in ascending order. SkipList is not thread safe.
+type SkipList[K cmp.Ordered, V any] struct {
+	head   *skipListNode[K, V]
+	level  int
+	length int
+}
+
+type skipListNode[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*skipListNode[K, V]
+}
+
+// Returns a random level in [1, skipListMaxLevel], level n+1 has
+// a quarter of the nodes of level n.
+func randomLevel() int {
+	level := 1
+	for level < skipListMaxLevel && rand.Intn(4) == 0 {
+		level++
+	}
+	return level
+}
+
+// Returns the last node before key on every level.
+func (l *SkipList[K, V]) predecessors(key K) []*skipListNode[K, V] {
+	update := make([]*skipListNode[K, V], skipListMaxLevel)
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+		update[i] = x
+	}
+	return update
+}
+
+// Returns the first node with a key not less than key, nil if none.
+func (l *SkipList[K, V]) seek(key K) *skipListNode[K, V] {
+	x := l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && x.next[i].key < key {
+			x = x.next[i]
+		}
+	}
+	return x.next[0]
+}
+
+// Sets the value of key, replacing the old value if key exists.
+func (l *SkipList[K, V]) Set(key K, val V) {
+	update := l.predecessors(key)
+	if x := update[0].next[0]; x != nil && x.key == key {
+		x.value = val
+		return
+	}
+
+	level := randomLevel()
+	if level > l.level {
+		for i := l.level; i < level; i++ {
+			update[i] = l.head
+		}
+		l.level = level
+	}
+
+	x := &skipListNode[K, V]{key: key, value: val, next: make([]*skipListNode[K, V], level)}
+	for i := 0; i < level; i++ {
+		x.next[i] = update[i].next[i]
+		update[i].next[i] = x
+	}
+	l.length++
+}
+
+// Returns the value of key, false if key does not exist.
+func (l *SkipList[K, V]) Get(key K) (V, bool) {
+	if x := l.seek(key); x != nil && x.key == key {
+		return x.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Removes key, returns false if key does not exist.
+func (l *SkipList[K, V]) Delete(key K) bool {
+	update := l.predecessors(key)
+	x := update[0].next[0]
+	if x == nil || x.key != key {
+		return false
+	}
+
+	for i := 0; i < len(x.next); i++ {
+		update[i].next[i] = x.next[i]
+	}
+	for l.level > 1 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+	l.length--
+	return true
+}
+
+// Calls f with every key in [lo, hi] and its value in ascending order,
+// stops if f returns false.
+func (l *SkipList[K, V]) Range(lo, hi K, f func(K, V) bool) {
+	for x := l.seek(lo); x != nil && x.key <= hi; x = x.next[0] {
+		if !f(x.key, x.value) {
+			return
+		}
+	}
+}
+
+// Returns the number of keys.
+func (l *SkipList[K, V]) Len() int {
+	return l.length
+}
+
+// Returns all keys in ascending order.
+func (l *SkipList[K, V]) Keys() []K {
+	keys := make([]K, 0, l.length)
+	for x := l.head.next[0]; x != nil; x = x.next[0] {
+		keys = append(keys, x.key)
+	}
+	return keys
+}
@@ -0,0 +1,112 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"math/rand"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestSkipListBasic(t *testing.T) {
+	l := NewSkipList[string, int]()
+	l.Set("b", 2)
+	l.Set("a", 1)
+	l.Set("c", 3)
+	l.Set("b", 20)
+
+	if l.Len() != 3 || !reflect.DeepEqual(l.Keys(), []string{"a", "b", "c"}) {
+		t.Fatal()
+	}
+	if v, ok := l.Get("b"); !ok || v != 20 {
+		t.Fatal()
+	}
+	if _, ok := l.Get("d"); ok {
+		t.Fatal()
+	}
+
+	if !l.Delete("b") || l.Delete("b") || l.Len() != 2 {
+		t.Fatal()
+	}
+	if _, ok := l.Get("b"); ok {
+		t.Fatal()
+	}
+}
+
+func TestSkipListRange(t *testing.T) {
+	l := NewSkipList[int, string]()
+	for _, k := range []int{5, 1, 9, 3, 7} {
+		l.Set(k, "")
+	}
+
+	keys := []int{}
+	l.Range(3, 7, func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if !reflect.DeepEqual(keys, []int{3, 5, 7}) {
+		t.Fatal(keys)
+	}
+
+	keys = []int{}
+	l.Range(2, 100, func(k int, v string) bool {
+		keys = append(keys, k)
+		return len(keys) < 2
+	})
+	if !reflect.DeepEqual(keys, []int{3, 5}) {
+		t.Fatal(keys)
+	}
+}
+
+// Compares the skip list against a map after random operations.
+func TestSkipListRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	l := NewSkipList[int, int]()
+	m := map[int]int{}
+
+	for i := 0; i < 5000; i++ {
+		k := r.Intn(500)
+		if r.Intn(3) == 0 {
+			_, ok := m[k]
+			if l.Delete(k) != ok {
+				t.Fatal()
+			}
+			delete(m, k)
+		} else {
+			l.Set(k, i)
+			m[k] = i
+		}
+	}
+
+	keys := []int{}
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	if l.Len() != len(m) || !reflect.DeepEqual(l.Keys(), keys) {
+		t.Fatal()
+	}
+
+	for i := 0; i < 100; i++ {
+		lo := r.Intn(500)
+		hi := lo + r.Intn(100)
+
+		expected := []int{}
+		for _, k := range keys {
+			if k >= lo && k <= hi {
+				expected = append(expected, k, m[k])
+			}
+		}
+		actual := []int{}
+		l.Range(lo, hi, func(k, v int) bool {
+			actual = append(actual, k, v)
+			return true
+		})
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatal(lo, hi)
+		}
+	}
+}
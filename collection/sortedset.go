@@ -0,0 +1,299 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"sort"
+)
+
+// Create a new sorted set with elements, ordered by less.
+// Two elements a and b are the same element if neither less(a, b)
+// nor less(b, a) is true.
+func NewSortedSet(less func(a, b interface{}) bool, elements ...interface{}) SortedSet {
+	set := &sortedSet{less: less}
+	for _, element := range elements {
+		set.Add(element)
+	}
+	return set
+}
+
+// A set that keeps its elements in sorted order.
+// ToSlice and Foreach yield the elements in ascending order,
+// lookups are logarithmic. SortedSet is not thread safe.
+type SortedSet interface {
+	Set
+
+	// Returns the smallest element, false if this set is empty.
+	First() (interface{}, bool)
+
+	// Returns the largest element, false if this set is empty.
+	Last() (interface{}, bool)
+}
+
+type sortedSet struct {
+	less     func(a, b interface{}) bool
+	elements []interface{}
+}
+
+// Returns the index of v, or the index v would be inserted at.
+func (s *sortedSet) search(v interface{}) (int, bool) {
+	i := sort.Search(len(s.elements), func(i int) bool {
+		return !s.less(s.elements[i], v)
+	})
+	return i, i < len(s.elements) && !s.less(v, s.elements[i])
+}
+
+func (s *sortedSet) Size() int {
+	return len(s.elements)
+}
+
+func (s *sortedSet) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+func (s *sortedSet) Contains(v interface{}) bool {
+	_, ok := s.search(v)
+	return ok
+}
+
+func (s *sortedSet) ContainsAll(elements ...interface{}) bool {
+	for _, v := range elements {
+		if !s.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sortedSet) ContainsAny(elements ...interface{}) bool {
+	for _, v := range elements {
+		if s.Contains(v) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *sortedSet) ToSlice() []interface{} {
+	values := make([]interface{}, len(s.elements))
+	copy(values, s.elements)
+	return values
+}
+
+func (s *sortedSet) ToSortedSlice(less func(a, b interface{}) bool) []interface{} {
+	return sortedElements(s, less)
+}
+
+func (s *sortedSet) ToSortedStringSlice() []string {
+	return sortedStrings(s)
+}
+
+func (s *sortedSet) ToTypedSlice(out interface{}) {
+	typedSlice(s, out)
+}
+
+func (s *sortedSet) Add(v interface{}) bool {
+	i, ok := s.search(v)
+	if ok {
+		return true
+	}
+	s.elements = append(s.elements, nil)
+	copy(s.elements[i+1:], s.elements[i:])
+	s.elements[i] = v
+	return false
+}
+
+func (s *sortedSet) With(values ...interface{}) Set {
+	for _, v := range values {
+		s.Add(v)
+	}
+	return s
+}
+
+func (s *sortedSet) Remove(v interface{}) bool {
+	i, ok := s.search(v)
+	if ok {
+		copy(s.elements[i:], s.elements[i+1:])
+		s.elements[len(s.elements)-1] = nil
+		s.elements = s.elements[:len(s.elements)-1]
+	}
+	return ok
+}
+
+func (s *sortedSet) Clear() {
+	s.elements = nil
+}
+
+func (s *sortedSet) CopyFrom(s1 Set) {
+	if s1 == Set(s) {
+		return
+	}
+	for i := range s.elements {
+		s.elements[i] = nil
+	}
+	s.elements = s.elements[:0]
+	s.Union(s1)
+}
+
+func (s *sortedSet) Union(s1 Set) {
+	if s1 == nil {
+		return
+	}
+	s1.Foreach(func(i interface{}) {
+		s.Add(i)
+	})
+}
+
+func (s *sortedSet) Merge(sets ...Set) {
+	for _, s1 := range sets {
+		s.Union(s1)
+	}
+}
+
+func (s *sortedSet) Intersect(s1 Set) {
+	if s1 == nil {
+		return
+	}
+	s.retain(s1.Contains)
+}
+
+func (s *sortedSet) Subtract(s1 Set) {
+	if s1 == nil {
+		return
+	}
+	s.retain(func(v interface{}) bool {
+		return !s1.Contains(v)
+	})
+}
+
+// Removes all elements not satisfied f, keeping the order.
+func (s *sortedSet) retain(f func(interface{}) bool) {
+	n := 0
+	for _, v := range s.elements {
+		if f(v) {
+			s.elements[n] = v
+			n++
+		}
+	}
+	for i := n; i < len(s.elements); i++ {
+		s.elements[i] = nil
+	}
+	s.elements = s.elements[:n]
+}
+
+func (s *sortedSet) IntersectSize(s1 Set) int {
+	return intersectSize(s, s1)
+}
+
+func (s *sortedSet) UnionSize(s1 Set) int {
+	return unionSize(s, s1)
+}
+
+func (s *sortedSet) IsSubset(s1 Set) bool {
+	if s1 == nil || s.Size() > s1.Size() {
+		return false
+	}
+
+	for _, v := range s.elements {
+		if !s1.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *sortedSet) IsEqual(s1 Set) bool {
+	if s1 == nil || s.Size() != s1.Size() {
+		return false
+	}
+	return s.IsSubset(s1)
+}
+
+func (s *sortedSet) Clone() Set {
+	return &sortedSet{s.less, s.ToSlice()}
+}
+
+func (s *sortedSet) Foreach(f func(interface{})) {
+	for _, v := range s.elements {
+		f(v)
+	}
+}
+
+func (s *sortedSet) Iterator() Iterator {
+	return &sliceIterator{elements: s.ToSlice()}
+}
+
+func (s *sortedSet) ForeachSorted(less func(a, b interface{}) bool, f func(interface{})) {
+	for _, v := range sortedElements(s, less) {
+		f(v)
+	}
+}
+
+// The mapped elements may not be ordered by less, so the result
+// is an unsorted set.
+func (s *sortedSet) Map(f func(interface{}) interface{}) Set {
+	result := NewSet()
+	for _, v := range s.elements {
+		result.Add(f(v))
+	}
+	return result
+}
+
+func (s *sortedSet) Filter(f func(interface{}) bool) Set {
+	result := &sortedSet{less: s.less}
+	for _, v := range s.elements {
+		if f(v) {
+			result.elements = append(result.elements, v)
+		}
+	}
+	return result
+}
+
+func (s *sortedSet) Partition(f func(interface{}) bool) (matched, unmatched Set) {
+	m, u := &sortedSet{less: s.less}, &sortedSet{less: s.less}
+	for _, v := range s.elements {
+		if f(v) {
+			m.elements = append(m.elements, v)
+		} else {
+			u.elements = append(u.elements, v)
+		}
+	}
+	return m, u
+}
+
+// The elements are aggregated in ascending order.
+func (s *sortedSet) Reduce(initial interface{}, f func(acc, element interface{}) interface{}) interface{} {
+	acc := initial
+	for _, v := range s.elements {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+func (s *sortedSet) String() string {
+	return formatElements(s.elements)
+}
+
+func (s *sortedSet) Hash() uint64 {
+	var h uint64
+	for _, v := range s.elements {
+		h ^= hashElement(v)
+	}
+	return h
+}
+
+func (s *sortedSet) First() (interface{}, bool) {
+	if len(s.elements) == 0 {
+		return nil, false
+	}
+	return s.elements[0], true
+}
+
+func (s *sortedSet) Last() (interface{}, bool) {
+	if len(s.elements) == 0 {
+		return nil, false
+	}
+	return s.elements[len(s.elements)-1], true
+}
@@ -0,0 +1,263 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func intLess(a, b interface{}) bool {
+	return a.(int) < b.(int)
+}
+
+func TestSortedSetBasic(t *testing.T) {
+	set := NewSortedSet(intLess, 3, 1, 2, 3)
+	if set.Size() != 3 || !set.Contains(1) ||
+		!set.Contains(2) || !set.Contains(3) || set.Contains(4) {
+		t.Fatal()
+	}
+
+	if !reflect.DeepEqual(set.ToSlice(), []interface{}{1, 2, 3}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetAddRemove(t *testing.T) {
+	set := NewSortedSet(intLess)
+	if set.Add(5) || set.Add(1) || set.Add(3) || !set.Add(3) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(set.ToSlice(), []interface{}{1, 3, 5}) {
+		t.Fatal()
+	}
+
+	if !set.Remove(3) || set.Remove(3) || set.Size() != 2 {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(set.ToSlice(), []interface{}{1, 5}) {
+		t.Fatal()
+	}
+
+	if set.With(4, 1).With(2) != set || !reflect.DeepEqual(set.ToSlice(), []interface{}{1, 2, 4, 5}) {
+		t.Fatal(set.ToSlice())
+	}
+
+	set.Clear()
+	if !set.IsEmpty() {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetFirstLast(t *testing.T) {
+	set := NewSortedSet(intLess, 4, 8, 2, 6)
+	first, ok1 := set.First()
+	last, ok2 := set.Last()
+	if !ok1 || !ok2 || first != 2 || last != 8 {
+		t.Fatal()
+	}
+
+	empty := NewSortedSet(intLess)
+	_, ok1 = empty.First()
+	_, ok2 = empty.Last()
+	if ok1 || ok2 {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetForeach(t *testing.T) {
+	set := NewSortedSet(intLess, 3, 1, 2)
+	values := []int{}
+	set.Foreach(func(i interface{}) {
+		values = append(values, i.(int))
+	})
+	if !reflect.DeepEqual(values, []int{1, 2, 3}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetOperations(t *testing.T) {
+	set1 := NewSortedSet(intLess, 1, 2, 3)
+	set1.Union(NewSet(2, 3, 4))
+	if !reflect.DeepEqual(set1.ToSlice(), []interface{}{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+
+	set1.Intersect(NewSet(2, 3, 5))
+	if !reflect.DeepEqual(set1.ToSlice(), []interface{}{2, 3}) {
+		t.Fatal()
+	}
+
+	set2 := NewSortedSet(intLess, 1, 2, 3)
+	set2.Subtract(NewSet(2))
+	if !reflect.DeepEqual(set2.ToSlice(), []interface{}{1, 3}) {
+		t.Fatal()
+	}
+
+	if !set2.IsSubset(NewSet(1, 2, 3)) || set2.IsSubset(NewSet(1)) ||
+		!set2.IsEqual(NewSet(3, 1)) || set2.IsEqual(NewSet(1, 2)) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetCloneMapFilter(t *testing.T) {
+	set1 := NewSortedSet(intLess, 1, 2, 3, 4)
+	set2 := set1.Clone()
+	set2.Add(5)
+	if set1.Size() != 4 || set2.Size() != 5 {
+		t.Fatal()
+	}
+
+	set3 := set1.Map(func(i interface{}) interface{} { return i.(int) * 10 })
+	if !set3.IsEqual(NewSet(10, 20, 30, 40)) {
+		t.Fatal()
+	}
+
+	set4 := set1.Filter(func(i interface{}) bool { return i.(int)%2 == 0 })
+	if !reflect.DeepEqual(set4.ToSlice(), []interface{}{2, 4}) {
+		t.Fatal()
+	}
+
+	if Hash(set1) != Hash(NewSet(4, 3, 2, 1)) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetReduce(t *testing.T) {
+	set := NewSortedSet(intLess, 3, 1, 2)
+	r := set.Reduce("", func(acc, element interface{}) interface{} {
+		return acc.(string) + fmt.Sprint(element)
+	})
+	if r != "123" {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetForeachSorted(t *testing.T) {
+	set := NewSortedSet(intLess, 1, 2, 3)
+	values := []interface{}{}
+	ForeachSorted(set, func(a, b interface{}) bool {
+		return a.(int) > b.(int)
+	}, func(i interface{}) {
+		values = append(values, i)
+	})
+	if !reflect.DeepEqual(values, []interface{}{3, 2, 1}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetContainsAllAny(t *testing.T) {
+	set := NewSortedSet(intLess, 1, 2, 3)
+	if !set.ContainsAll(1, 3) || set.ContainsAll(1, 4) || !set.ContainsAll() {
+		t.Fatal()
+	}
+	if !set.ContainsAny(4, 3) || set.ContainsAny(4, 5) || set.ContainsAny() {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetToSortedSlice(t *testing.T) {
+	set1 := NewSortedSet(intLess, 1, 2, 3)
+	s := set1.ToSortedSlice(func(a, b interface{}) bool {
+		return a.(int) > b.(int)
+	})
+	if !reflect.DeepEqual(s, []interface{}{3, 2, 1}) {
+		t.Fatal()
+	}
+
+	set2 := NewSortedSet(func(a, b interface{}) bool {
+		return len(a.(string)) < len(b.(string))
+	}, "ccc", "a", "bb")
+	if !reflect.DeepEqual(set2.ToSortedStringSlice(), []string{"a", "bb", "ccc"}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetMerge(t *testing.T) {
+	set := NewSortedSet(intLess, 5)
+	set.Merge(NewSet(3, 1), nil, NewSet(4))
+	if !reflect.DeepEqual(set.ToSlice(), []interface{}{1, 3, 4, 5}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetPartition(t *testing.T) {
+	set := NewSortedSet(intLess, 5, 4, 3, 2, 1)
+	even, odd := Partition(set, func(i interface{}) bool {
+		return i.(int)%2 == 0
+	})
+	if !reflect.DeepEqual(even.ToSlice(), []interface{}{2, 4}) ||
+		!reflect.DeepEqual(odd.ToSlice(), []interface{}{1, 3, 5}) {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetIntersectUnionSize(t *testing.T) {
+	set := NewSortedSet(intLess, 1, 2, 3)
+	if IntersectSize(set, NewSet(2, 3, 4, 5)) != 2 || UnionSize(set, NewSet(2, 3, 4, 5)) != 5 {
+		t.Fatal()
+	}
+	if set.(*sortedSet).IntersectSize(NewSet(2, 3, 4, 5)) != 2 || set.(*sortedSet).UnionSize(NewSet(2, 3, 4, 5)) != 5 {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetToTypedSlice(t *testing.T) {
+	var ints []int
+	ToTypedSlice(NewSortedSet(intLess, 3, 1, 2), &ints)
+	if !reflect.DeepEqual(ints, []int{1, 2, 3}) {
+		t.Fatal(ints)
+	}
+}
+
+func TestSortedSetCopyFrom(t *testing.T) {
+	set := NewSortedSet(intLess, 5, 1)
+	CopyFrom(set, NewSet(4, 2, 3))
+	if !reflect.DeepEqual(set.ToSlice(), []interface{}{2, 3, 4}) {
+		t.Fatal(set.ToSlice())
+	}
+	CopyFrom(set, nil)
+	if !set.IsEmpty() {
+		t.Fatal()
+	}
+}
+
+func TestSortedSetString(t *testing.T) {
+	set := NewSortedSet(func(a, b interface{}) bool { return a.(int) > b.(int) }, 1, 3, 2)
+	if s := set.String(); s != "{3, 2, 1}" {
+		t.Fatal(s)
+	}
+}
+
+func TestSortedSetIterator(t *testing.T) {
+	// Merge two sorted sets by advancing their iterators together.
+	a := NewIterator(NewSortedSet(intLess, 1, 4, 6))
+	b := NewIterator(NewSortedSet(intLess, 2, 3, 7))
+	merged := []interface{}{}
+	var x, y interface{}
+	if a.HasNext() {
+		x = a.Next()
+	}
+	if b.HasNext() {
+		y = b.Next()
+	}
+	for x != nil || y != nil {
+		if y == nil || (x != nil && x.(int) < y.(int)) {
+			merged, x = append(merged, x), nil
+			if a.HasNext() {
+				x = a.Next()
+			}
+		} else {
+			merged, y = append(merged, y), nil
+			if b.HasNext() {
+				y = b.Next()
+			}
+		}
+	}
+	if !reflect.DeepEqual(merged, []interface{}{1, 2, 3, 4, 6, 7}) {
+		t.Fatal(merged)
+	}
+}
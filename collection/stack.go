@@ -0,0 +1,116 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+// Create a new stack with elements, the last one is on the top.
+func NewStack(elements ...interface{}) Stack {
+	s := &baseStack{}
+	for _, element := range elements {
+		s.Push(element)
+	}
+	return s
+}
+
+// A last-in-first-out collection.
+// Stack is not thread safe.
+type Stack interface {
+
+	// Returns the number of elements in this stack.
+	Len() int
+
+	// Returns true if this stack contains no elements.
+	IsEmpty() bool
+
+	// Adds v on the top of this stack.
+	Push(v interface{})
+
+	// Removes and returns the top element.
+	// Returns false if this stack is empty.
+	Pop() (interface{}, bool)
+
+	// Returns the top element without removing it.
+	// Returns false if this stack is empty.
+	Peek() (interface{}, bool)
+
+	// Iterate the elements from top to bottom without removing them,
+	// and invoke f by every element.
+	Foreach(f func(v interface{}))
+
+	// Returns a snapshot of the elements ordered from top to bottom.
+	// The caller is free to modify the returned slice.
+	ToSlice() []interface{}
+}
+
+type baseStack struct {
+	TypedStack[interface{}]
+}
+
+// Create a new empty stack of elements of type T.
+func NewTypedStack[T any]() *TypedStack[T] {
+	return &TypedStack[T]{}
+}
+
+// Same as Stack, but type safe. The zero value is an empty stack.
+// TypedStack is not thread safe.
+type TypedStack[T any] struct {
+	// The top element is the last one.
+	elements []T
+}
+
+// Returns the number of elements in the stack.
+func (s *TypedStack[T]) Len() int {
+	return len(s.elements)
+}
+
+// Returns true if the stack contains no elements.
+func (s *TypedStack[T]) IsEmpty() bool {
+	return len(s.elements) == 0
+}
+
+// Adds v on the top of the stack.
+func (s *TypedStack[T]) Push(v T) {
+	s.elements = append(s.elements, v)
+}
+
+// Removes and returns the top element.
+// Returns false if the stack is empty.
+func (s *TypedStack[T]) Pop() (T, bool) {
+	var zero T
+	n := len(s.elements) - 1
+	if n < 0 {
+		return zero, false
+	}
+	v := s.elements[n]
+	s.elements[n] = zero
+	s.elements = s.elements[:n]
+	return v, true
+}
+
+// Returns the top element without removing it.
+// Returns false if the stack is empty.
+func (s *TypedStack[T]) Peek() (T, bool) {
+	if len(s.elements) == 0 {
+		var zero T
+		return zero, false
+	}
+	return s.elements[len(s.elements)-1], true
+}
+
+// Iterate the elements from top to bottom without removing them,
+// and invoke f by every element.
+func (s *TypedStack[T]) Foreach(f func(v T)) {
+	for i := len(s.elements) - 1; i >= 0; i-- {
+		f(s.elements[i])
+	}
+}
+
+// Returns a snapshot of the elements ordered from top to bottom.
+func (s *TypedStack[T]) ToSlice() []T {
+	result := make([]T, len(s.elements))
+	for i, v := range s.elements {
+		result[len(result)-1-i] = v
+	}
+	return result
+}
@@ -0,0 +1,81 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package collection
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStack(t *testing.T) {
+	s := NewStack(1, 2)
+	if s.Len() != 2 || s.IsEmpty() {
+		t.Fatal()
+	}
+	if v, ok := s.Peek(); !ok || v != 2 {
+		t.Fatal(v)
+	}
+
+	s.Push(3)
+	if v, ok := s.Pop(); !ok || v != 3 {
+		t.Fatal(v)
+	}
+	s.Push("a")
+	s.Push("b")
+	s.Pop()
+	if !reflect.DeepEqual(s.ToSlice(), []interface{}{"a", 2, 1}) {
+		t.Fatal(s.ToSlice())
+	}
+
+	values := []interface{}{}
+	s.Foreach(func(v interface{}) { values = append(values, v) })
+	if !reflect.DeepEqual(values, []interface{}{"a", 2, 1}) || s.Len() != 3 {
+		t.Fatal(values)
+	}
+
+	for !s.IsEmpty() {
+		s.Pop()
+	}
+	if _, ok := s.Pop(); ok {
+		t.Fatal()
+	}
+	if _, ok := s.Peek(); ok {
+		t.Fatal()
+	}
+	if len(s.ToSlice()) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestTypedStack(t *testing.T) {
+	s := NewTypedStack[string]()
+	s.Push("a")
+	s.Push("b")
+	s.Push("c")
+	s.Pop()
+	s.Push("d")
+	if !reflect.DeepEqual(s.ToSlice(), []string{"d", "b", "a"}) {
+		t.Fatal(s.ToSlice())
+	}
+
+	// The snapshot is not changed by later operations.
+	snapshot := s.ToSlice()
+	s.Pop()
+	if !reflect.DeepEqual(snapshot, []string{"d", "b", "a"}) ||
+		!reflect.DeepEqual(s.ToSlice(), []string{"b", "a"}) {
+		t.Fatal(snapshot, s.ToSlice())
+	}
+
+	joined := ""
+	s.Foreach(func(v string) { joined += v })
+	if joined != "ba" || s.Len() != 2 {
+		t.Fatal(joined)
+	}
+
+	var zero TypedStack[int]
+	if v, ok := zero.Pop(); ok || v != 0 || !zero.IsEmpty() {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+// Package concurrent provides helpers for concurrent programming
+// that complement the standard "sync" package.
+package concurrent
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OnceErr is an object that will perform exactly one successful call
+// of an initialization function, and remember its error.
+// Unlike sync.Once, the error returned by the function is cached and
+// returned to every caller of Do.
+// The zero value is ready to use. OnceErr must not be copied after first use.
+type OnceErr struct {
+	mu   sync.Mutex
+	done uint32
+	err  error
+}
+
+// Calls the function f if and only if Do is being called for the first
+// time for this OnceErr (or the first time since Reset), and returns
+// the error of f. Later calls return the cached error without calling f.
+// Concurrent callers block until the first call of f returns.
+// If f panics, the call is not recorded and the next Do will call f again.
+func (o *OnceErr) Do(f func() error) error {
+	if atomic.LoadUint32(&o.done) == 1 {
+		return o.err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.done == 0 {
+		o.err = f()
+		atomic.StoreUint32(&o.done, 1)
+	}
+	return o.err
+}
+
+// Forgets the cached result, so the next Do will call its function again.
+// It is mainly intended for tests, and must not be called concurrently with Do.
+func (o *OnceErr) Reset() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.err = nil
+	atomic.StoreUint32(&o.done, 0)
+}
@@ -0,0 +1,107 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package concurrent
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestOnceErrDo(t *testing.T) {
+	var once OnceErr
+	calls := 0
+	for i := 0; i < 3; i++ {
+		err := once.Do(func() error {
+			calls++
+			return nil
+		})
+		if err != nil {
+			t.Fatal()
+		}
+	}
+	if calls != 1 {
+		t.Fatal()
+	}
+}
+
+func TestOnceErrCachesError(t *testing.T) {
+	var once OnceErr
+	e := errors.New("init failed")
+	calls := 0
+	f := func() error {
+		calls++
+		return e
+	}
+	if once.Do(f) != e || once.Do(f) != e {
+		t.Fatal()
+	}
+	if calls != 1 {
+		t.Fatal()
+	}
+}
+
+func TestOnceErrReset(t *testing.T) {
+	var once OnceErr
+	e := errors.New("init failed")
+	once.Do(func() error { return e })
+	once.Reset()
+
+	calls := 0
+	err := once.Do(func() error {
+		calls++
+		return nil
+	})
+	if err != nil || calls != 1 {
+		t.Fatal()
+	}
+}
+
+func TestOnceErrPanic(t *testing.T) {
+	var once OnceErr
+	func() {
+		defer func() { recover() }()
+		once.Do(func() error { panic("boom") })
+	}()
+
+	calls := 0
+	once.Do(func() error {
+		calls++
+		return nil
+	})
+	if calls != 1 {
+		t.Fatal()
+	}
+}
+
+func TestOnceErrConcurrent(t *testing.T) {
+	var once OnceErr
+	var calls int32
+	e := errors.New("init failed")
+
+	var wg sync.WaitGroup
+	errs := make([]error, 16)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = once.Do(func() error {
+				atomic.AddInt32(&calls, 1)
+				return e
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatal()
+	}
+	for _, err := range errs {
+		if err != e {
+			t.Fatal()
+		}
+	}
+}
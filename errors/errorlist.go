@@ -0,0 +1,60 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"time"
+)
+
+// ErrorList collects multiple errors, e.g. in validation code that reports
+// every problem instead of failing fast. The zero value is an empty list.
+// ErrorList is not thread safe.
+type ErrorList struct {
+	errs []error
+}
+
+// Appends err to the list, nil is ignored.
+func (l *ErrorList) Add(err error) {
+	if err != nil {
+		l.errs = append(l.errs, err)
+	}
+}
+
+// Appends err to the list if cond is true, nil is ignored.
+func (l *ErrorList) AddIf(cond bool, err error) {
+	if cond {
+		l.Add(err)
+	}
+}
+
+// This returns the number of errors in the list.
+func (l *ErrorList) Len() int {
+	return len(l.errs)
+}
+
+// Invokes f with every error in the list, in the order they were added.
+func (l *ErrorList) Each(f func(error)) {
+	for _, err := range l.errs {
+		f(err)
+	}
+}
+
+// This returns nil if the list is empty, otherwise a single Error whose
+// message joins all messages with "; ", and whose stack trace is the
+// current one.
+func (l *ErrorList) Err() error {
+	if len(l.errs) == 0 {
+		return nil
+	}
+
+	stack, context := StackTrace()
+	return &baseError{
+		message:   joinMessages(l.errs),
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      DefaultErrCode,
+	}
+}
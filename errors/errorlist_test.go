@@ -0,0 +1,49 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorListEmpty(t *testing.T) {
+	var l ErrorList
+	l.Add(nil)
+	l.AddIf(false, New("skipped"))
+	if l.Len() != 0 || l.Err() != nil {
+		t.Fatal()
+	}
+}
+
+func TestErrorList(t *testing.T) {
+	var l ErrorList
+	l.Add(New("name is required"))
+	l.AddIf(true, fmt.Errorf("age is negative"))
+	l.AddIf(false, New("skipped"))
+	l.Add(Wrap(fmt.Errorf("bad format"), "email is invalid"))
+
+	if l.Len() != 3 {
+		t.Fatal()
+	}
+
+	n := 0
+	l.Each(func(err error) { n++ })
+	if n != 3 {
+		t.Fatal()
+	}
+
+	err, ok := l.Err().(Error)
+	if !ok {
+		t.Fatal()
+	}
+	if err.Message() != "name is required; age is negative; email is invalid bad format" {
+		t.Fatal(err.Message())
+	}
+	if strings.Index(err.Stack(), "TestErrorList") == -1 {
+		t.Fatal()
+	}
+}
@@ -11,7 +11,10 @@ import (
 	"bytes"
 	"fmt"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Default value if the error code is not defined.
@@ -36,30 +39,59 @@ type Error interface {
 	// If the error code is not pre-defined, return is DefaultErrCode.
 	Code() int
 
+	// This sets the error code.
+	SetCode(code int)
+
+	// This returns the severity.
+	// If the severity is not set, return is SeverityUnknown.
+	Severity() Severity
+
 	// This returns the wrapped error. Nil if not wrap another error.
 	Inner() error
 
+	// This returns the time the error was created.
+	CreatedAt() time.Time
+
+	// This returns the ID of the goroutine the error was created on,
+	// parsed from the stack trace. 0 if unknown.
+	GoroutineID() int64
+
 	// Implements the built-in error interface.
 	Error() string
 }
 
 // Base standard struct for interface 'Error'.
 type baseError struct {
-	message string
-	stack   string
-	context string
-	code    int
-	inner   error
+	message   string
+	stack     string
+	context   string
+	code      int
+	inner     error
+	fields    map[string]interface{}
+	createdAt time.Time
+	severity  Severity
+
+	// The fmt.Printf-style format and args of message, kept for the
+	// translator, see translatedMessage.
+	format string
+	args   []interface{}
+
+	// Created without a message of its own, by WithCode or by Chain with no
+	// messages. Message and DefaultError skip it.
+	noMessage bool
 }
 
 // This returns the error string without stack trace information.
+// Sensitive substrings are masked, see SetRedactors.
 func Message(err interface{}) string {
 	switch e := err.(type) {
 	case Error:
 		err := Error(e)
 		ret := []string{}
 		for err != nil {
-			ret = append(ret, err.Message())
+			if !noMessage(err) {
+				ret = append(ret, err.Message())
+			}
 			innerErr := err.Inner()
 
 			if innerErr == nil {
@@ -73,9 +105,9 @@ func Message(err interface{}) string {
 				break
 			}
 		}
-		return strings.Join(ret, " ")
+		return redact(strings.Join(ret, " "))
 	case runtime.Error:
-		return runtime.Error(e).Error()
+		return redact(runtime.Error(e).Error())
 	default:
 		return "Passed a non-error to Message"
 	}
@@ -96,6 +128,31 @@ func (e *baseError) Context() string {
 	return e.context
 }
 
+// This returns the number of frames in the stack trace, 0 if there is no stack.
+func (e *baseError) StackDepth() int {
+	return stackDepth(e.stack)
+}
+
+// This returns the number of frames in the stack trace of the first Error
+// in the chain of err, see Extract. 0 if there is no Error in the chain.
+func StackDepth(err error) int {
+	if e, ok := Extract[Error](err); ok {
+		return stackDepth(e.Stack())
+	}
+	return 0
+}
+
+// Counts the frames of a stack trace, each frame has a tab-indented file line.
+func stackDepth(stack string) int {
+	n := 0
+	for _, line := range strings.Split(stack, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			n++
+		}
+	}
+	return n
+}
+
 // This set the error code.
 func (e *baseError) SetCode(code int) {
 	e.code = code
@@ -106,11 +163,53 @@ func (e *baseError) Code() int {
 	return e.code
 }
 
+// This returns the severity.
+func (e *baseError) Severity() Severity {
+	return e.severity
+}
+
 // This returns the wrapped error, if there is one.
 func (e *baseError) Inner() error {
 	return e.inner
 }
 
+// This returns the ID of the goroutine the error was created on.
+func (e *baseError) GoroutineID() int64 {
+	return goroutineID(e.stack)
+}
+
+// Parse the goroutine ID from the header line of a stack trace,
+// e.g. "goroutine 42 [running]:". 0 if there is no header.
+func goroutineID(stack string) int64 {
+	if !strings.HasPrefix(stack, "goroutine ") {
+		return 0
+	}
+	fields := strings.Fields(stack[len("goroutine "):])
+	if len(fields) == 0 {
+		return 0
+	}
+	id, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
+
+// This returns the time the error was created.
+func (e *baseError) CreatedAt() time.Time {
+	return e.createdAt
+}
+
+// This returns the creation time of the first Error in the chain of err,
+// e.g. of an Error wrapped by fmt.Errorf with %w, see Extract.
+// Return false if there is no Error in the chain.
+func CreatedAt(err error) (time.Time, bool) {
+	if e, ok := Extract[Error](err); ok {
+		return e.CreatedAt(), true
+	}
+	return time.Time{}, false
+}
+
 // This returns a string with all available error information,
 // including inner errors that are wrapped by this errors.
 func (e *baseError) Error() string {
@@ -118,12 +217,23 @@ func (e *baseError) Error() string {
 }
 
 // A default implementation of the Error method of the error interface.
+// Sensitive substrings of the messages are masked, see SetRedactors.
+// Messages are translated by the global translator if one is set, see
+// SetGlobalTranslator.
 func DefaultError(e Error) string {
 
 	errLines := []string{"ERROR:"}
 	var origStack string
 	fillErrorInfo(e, &errLines, &origStack)
+	for i := range errLines {
+		errLines[i] = redact(errLines[i])
+	}
 
+	errLines = append(errLines, "")
+	errLines = append(errLines, "CREATED: "+e.CreatedAt().UTC().Format(time.RFC3339))
+	if id := e.GoroutineID(); id != 0 {
+		errLines = append(errLines, "GOROUTINE: "+strconv.FormatInt(id, 10))
+	}
 	errLines = append(errLines, "")
 	errLines = append(errLines, "ORIGINAL STACK TRACE:")
 	errLines = append(errLines, origStack)
@@ -139,7 +249,16 @@ func fillErrorInfo(err error, errLines *[]string, origStack *string) {
 
 	e, ok := err.(Error)
 	if ok {
-		*errLines = append(*errLines, e.Message())
+		b, ok := asBaseError(e)
+		switch {
+		case !ok:
+			*errLines = append(*errLines, e.Message())
+		case !b.noMessage:
+			*errLines = append(*errLines, b.translatedMessage())
+		}
+		if ok {
+			*errLines = append(*errLines, b.fieldLines()...)
+		}
 		*origStack = e.Stack()
 		fillErrorInfo(e.Inner(), errLines, origStack)
 	} else {
@@ -147,27 +266,135 @@ func fillErrorInfo(err error, errLines *[]string, origStack *string) {
 	}
 }
 
+// This returns the baseError of e, also of an Error returned by Join.
+func asBaseError(e Error) (*baseError, bool) {
+	switch b := e.(type) {
+	case *baseError:
+		return b, true
+	case *joinError:
+		return b.baseError, true
+	}
+	return nil, false
+}
+
+// Check if e was created without a message of its own.
+func noMessage(e Error) bool {
+	b, ok := asBaseError(e)
+	return ok && b.noMessage
+}
+
+// This returns the structured context as "key=value" lines sorted by key.
+func (e *baseError) fieldLines() []string {
+	keys := make([]string, 0, len(e.fields))
+	for k := range e.fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	lines := make([]string, len(keys))
+	for i, k := range keys {
+		lines[i] = fmt.Sprintf("%s=%v", k, e.fields[k])
+	}
+	return lines
+}
+
 // This returns a new baseError initialized with the given message and
 // the current stack trace.
 func New(msg string) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: msg,
-		stack:   stack,
-		context: context,
-		code:    DefaultErrCode,
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      DefaultErrCode,
 	}
 }
 
+// Same as New, but the stack trace skips skip frames above the caller, with
+// the frame-counting convention of StackTraceSkip. A helper creating errors
+// for its callers passes 1, so the helper does not appear as the origin:
+//
+//	func newDBError(table string) errors.Error {
+//		return errors.NewSkip(1, "db failed on "+table) // starts at the caller of newDBError
+//	}
+//
+// A negative skip is treated as 0.
+func NewSkip(skip int, msg string) Error {
+	if skip < 0 {
+		skip = 0
+	}
+	stack, context := stackTrace(skip + 2)
+	return &baseError{
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      DefaultErrCode,
+	}
+}
+
+// Same as New, but with structured context given as alternating key and
+// value arguments, e.g. NewCtx("db failed", "table", "users", "id", 42).
+// A non-string key is formatted by fmt.Sprint, a missing last value is nil.
+func NewCtx(msg string, kvs ...interface{}) Error {
+	stack, context := StackTrace()
+	return &baseError{
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      DefaultErrCode,
+		fields:    toFields(kvs),
+	}
+}
+
+// Converts alternating key and value arguments to a map.
+func toFields(kvs []interface{}) map[string]interface{} {
+	fields := make(map[string]interface{}, (len(kvs)+1)/2)
+	for i := 0; i < len(kvs); i += 2 {
+		key, ok := kvs[i].(string)
+		if !ok {
+			key = fmt.Sprint(kvs[i])
+		}
+		var value interface{}
+		if i+1 < len(kvs) {
+			value = kvs[i+1]
+		}
+		fields[key] = value
+	}
+	return fields
+}
+
+// This returns the structured context value of key, searching err and
+// then its inner errors. The outermost value wins.
+func GetContext(err Error, key string) (interface{}, bool) {
+	var e error = err
+	for e != nil {
+		ee, ok := e.(Error)
+		if !ok {
+			break
+		}
+		if b, ok := asBaseError(ee); ok {
+			if value, ok := b.fields[key]; ok {
+				return value, true
+			}
+		}
+		e = ee.Inner()
+	}
+	return nil, false
+}
+
 // This returns a new baseError initialized with the given message, error code and
 // the current stack trace.
 func NewByCode(code int, msg string) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: msg,
-		stack:   stack,
-		context: context,
-		code:    code,
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      code,
 	}
 }
 
@@ -175,10 +402,13 @@ func NewByCode(code int, msg string) Error {
 func Newf(format string, args ...interface{}) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: fmt.Sprintf(format, args...),
-		stack:   stack,
-		context: context,
-		code:    DefaultErrCode,
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      DefaultErrCode,
 	}
 }
 
@@ -186,10 +416,13 @@ func Newf(format string, args ...interface{}) Error {
 func NewfByCode(code int, format string, args ...interface{}) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: fmt.Sprintf(format, args...),
-		stack:   stack,
-		context: context,
-		code:    code,
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      code,
 	}
 }
 
@@ -197,23 +430,44 @@ func NewfByCode(code int, format string, args ...interface{}) Error {
 func Wrap(err error, msg string) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: msg,
-		stack:   stack,
-		context: context,
-		inner:   err,
-		code:    DefaultErrCode,
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      DefaultErrCode,
 	}
 }
 
+// Same as Wrap, but with structured context fields, see NewCtx.
+// The fields map is copied.
+func Wrapc(err error, msg string, fields map[string]interface{}) Error {
+	stack, context := StackTrace()
+	e := &baseError{
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      DefaultErrCode,
+		fields:    make(map[string]interface{}, len(fields)),
+	}
+	for k, v := range fields {
+		e.fields[k] = v
+	}
+	return e
+}
+
 // Wraps another error in a new baseError with error code information.
 func WrapByCode(code int, err error, msg string) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: msg,
-		stack:   stack,
-		context: context,
-		inner:   err,
-		code:    code,
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      code,
 	}
 }
 
@@ -221,11 +475,72 @@ func WrapByCode(code int, err error, msg string) Error {
 func Wrapf(err error, format string, args ...interface{}) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: fmt.Sprintf(format, args...),
-		stack:   stack,
-		context: context,
-		inner:   err,
-		code:    DefaultErrCode,
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      DefaultErrCode,
+	}
+}
+
+// Wraps base with each message in turn, the first message is the innermost.
+// The stack trace is captured once, and shared by all the wrappers.
+// Example: Chain(err, "read row", "load user") is the same as
+// Wrap(Wrap(err, "read row"), "load user").
+func Chain(base error, messages ...string) Error {
+	stack, context := StackTrace()
+	createdAt := time.Now()
+
+	e := &baseError{
+		stack:     stack,
+		context:   context,
+		createdAt: createdAt,
+		inner:     base,
+		code:      DefaultErrCode,
+		noMessage: len(messages) == 0,
+	}
+	for i, msg := range messages {
+		if i > 0 {
+			e = &baseError{
+				stack:     stack,
+				context:   context,
+				createdAt: createdAt,
+				inner:     e,
+				code:      DefaultErrCode,
+			}
+		}
+		e.message = msg
+	}
+	return e
+}
+
+// Same as Wrapf, but wraps *errp in place if it is not nil, to annotate a
+// named error result in one line:
+//
+//	func load(name string) (err error) {
+//		defer errors.Deferf(&err, "load %s", name)
+//		...
+//	}
+//
+// The stack trace starts at the function deferring Deferf.
+func Deferf(errp *error, format string, args ...interface{}) {
+	if errp == nil || *errp == nil {
+		return
+	}
+
+	stack, context := stackTrace(2)
+	*errp = &baseError{
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     *errp,
+		code:      DefaultErrCode,
 	}
 }
 
@@ -233,14 +548,111 @@ func Wrapf(err error, format string, args ...interface{}) Error {
 func WrapfByCode(code int, err error, format string, args ...interface{}) Error {
 	stack, context := StackTrace()
 	return &baseError{
-		message: fmt.Sprintf(format, args...),
-		stack:   stack,
-		context: context,
-		inner:   err,
-		code:    code,
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      code,
+	}
+}
+
+// Same as Wrap, but the new error takes the code of err if err is an Error
+// with a code other than DefaultErrCode.
+func WrapPreserveCode(err error, msg string) Error {
+	stack, context := StackTrace()
+	return &baseError{
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      innerCode(err),
 	}
 }
 
+// Same as WrapPreserveCode, but with fmt.Printf-style parameters.
+func WrapfPreserveCode(err error, format string, args ...interface{}) Error {
+	stack, context := StackTrace()
+	return &baseError{
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      innerCode(err),
+	}
+}
+
+// Returns the code of err if it is an Error, otherwise DefaultErrCode.
+func innerCode(err error) int {
+	if e, ok := err.(Error); ok {
+		return e.Code()
+	}
+	return DefaultErrCode
+}
+
+// This returns an Error with the given error code.
+// If err is an Error its code is set in place, otherwise err is wrapped
+// in a new baseError with the code and an empty message.
+// Returns nil if err is nil.
+func WithCode(err error, code int) Error {
+	if err == nil {
+		return nil
+	}
+	if e, ok := err.(Error); ok {
+		e.SetCode(code)
+		return e
+	}
+
+	stack, context := StackTrace()
+	return &baseError{
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		inner:     err,
+		code:      code,
+		noMessage: true,
+	}
+}
+
+// This returns true if a and b are both nil, or both Errors with the same
+// Code() and Message(), ignoring the stack trace. Other errors are equal
+// only if they are the same value.
+func Equal(a, b error) bool {
+	return compare(a, b, func(x, y Error) bool {
+		return x.Code() == y.Code() && x.Message() == y.Message()
+	})
+}
+
+// Same as Equal, but only compares Code().
+func EqualCode(a, b error) bool {
+	return compare(a, b, func(x, y Error) bool {
+		return x.Code() == y.Code()
+	})
+}
+
+// Same as Equal, but only compares Message().
+func EqualMessage(a, b error) bool {
+	return compare(a, b, func(x, y Error) bool {
+		return x.Message() == y.Message()
+	})
+}
+
+// Compares a and b by eq if both are Errors, otherwise by ==.
+func compare(a, b error, eq func(x, y Error) bool) bool {
+	x, ok1 := a.(Error)
+	y, ok2 := b.(Error)
+	if ok1 && ok2 {
+		return eq(x, y)
+	}
+	return a == b
+}
+
 // Returns a copy of the error with the stack trace field populated and any
 // other shared initialization; skips 'skip' levels of the stack trace.
 // NOTE: This panics on any error.
@@ -293,10 +705,29 @@ func stackTrace(skip int) (current, context string) {
 	}
 
 	strippedBuf.Write(buf[startIndex:index])
-	return strippedBuf.String(), string(buf[index:])
+	return filterStack(strippedBuf.String()), string(buf[index:])
 }
 
 // This returns the current stack trace string.
 func StackTrace() (current, context string) {
 	return stackTrace(3)
 }
+
+// This returns the current stack trace string, skipping skip frames above
+// the caller. 0 starts at the function calling StackTraceSkip, 1 at its
+// caller, and so on. A helper passes 1 so it does not appear as the origin,
+// e.g. in the context of a log line:
+//
+//	func logFailure(msg string) {
+//		stack, _ := errors.StackTraceSkip(1) // starts at the caller of logFailure
+//		log.Printf("%s\n%s", msg, stack)
+//	}
+//
+// Use NewSkip to create an Error with such a stack trace.
+// A negative skip is treated as 0.
+func StackTraceSkip(skip int) (current, context string) {
+	if skip < 0 {
+		skip = 0
+	}
+	return stackTrace(skip + 2)
+}
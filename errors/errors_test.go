@@ -6,8 +6,10 @@ package errors
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestStackTrace(t *testing.T) {
@@ -16,7 +18,7 @@ func TestStackTrace(t *testing.T) {
 	e := er.(*baseError)
 
 	if e.message != testMsg {
-		t.Error("error message %s != expected %s", e.message, testMsg)
+		t.Errorf("error message %s != expected %s", e.message, testMsg)
 	}
 
 	if strings.Index(e.stack, "errors/errors.go") != -1 {
@@ -55,3 +57,296 @@ func TestWrappedError(t *testing.T) {
 		t.Errorf("couldn't find outer error message in:\n%s", errorStr)
 	}
 }
+
+func TestWithCode(t *testing.T) {
+	e1 := New("not found")
+	e2 := WithCode(e1, 404)
+	if e2 != e1 || e2.Code() != 404 {
+		t.Fatal()
+	}
+
+	inner := fmt.Errorf("timeout")
+	e3 := WithCode(inner, 504)
+	if e3.Code() != 504 || e3.Inner() != inner {
+		t.Fatal()
+	}
+	if Message(e3) != "timeout" {
+		t.Fatal()
+	}
+	if strings.Index(e3.Error(), "ERROR:\ntimeout") == -1 {
+		t.Fatal(e3.Error())
+	}
+
+	// Other errors with an empty message keep it, as before.
+	if Message(Wrap(inner, "")) != " timeout" {
+		t.Fatal(Message(Wrap(inner, "")))
+	}
+	if strings.Index(e3.Stack(), "TestWithCode") == -1 {
+		t.Fatal()
+	}
+
+	if WithCode(nil, 500) != nil {
+		t.Fatal()
+	}
+}
+
+func TestSetCode(t *testing.T) {
+	var err Error = Wrap(fmt.Errorf("inner"), "outer")
+	err.SetCode(400)
+	if err.Code() != 400 {
+		t.Fatal()
+	}
+}
+
+func TestNewCtx(t *testing.T) {
+	inner := NewCtx("db failed", "table", "users", "id", 42)
+	outer := Wrap(inner, "load user")
+
+	if v, ok := GetContext(outer, "table"); !ok || v != "users" {
+		t.Fatal()
+	}
+	if v, ok := GetContext(outer, "id"); !ok || v != 42 {
+		t.Fatal()
+	}
+	if _, ok := GetContext(outer, "missing"); ok {
+		t.Fatal()
+	}
+
+	errorStr := outer.Error()
+	if strings.Index(errorStr, "db failed\nid=42\ntable=users\n") == -1 {
+		t.Fatalf("couldn't find context in:\n%s", errorStr)
+	}
+}
+
+func TestNewCtxOddArgs(t *testing.T) {
+	err := NewCtx("failed", 1, "one", "dangling")
+	if v, ok := GetContext(err, "1"); !ok || v != "one" {
+		t.Fatal()
+	}
+	if v, ok := GetContext(err, "dangling"); !ok || v != nil {
+		t.Fatal()
+	}
+}
+
+func TestWrapPreserveCode(t *testing.T) {
+	inner := NewByCode(404, "not found")
+	if Wrap(inner, "load user").Code() != DefaultErrCode {
+		t.Fatal()
+	}
+	if WrapPreserveCode(inner, "load user").Code() != 404 ||
+		WrapfPreserveCode(inner, "load user %d", 1).Code() != 404 {
+		t.Fatal()
+	}
+
+	if WrapPreserveCode(New("failed"), "load user").Code() != DefaultErrCode ||
+		WrapPreserveCode(fmt.Errorf("failed"), "load user").Code() != DefaultErrCode {
+		t.Fatal()
+	}
+
+	e := WrapfPreserveCode(inner, "load user %d", 1)
+	if e.Message() != "load user 1" || strings.Index(e.Stack(), "TestWrapPreserveCode") == -1 {
+		t.Fatal()
+	}
+}
+
+func TestStackDepth(t *testing.T) {
+	e1 := New("failed")
+	e2 := func() Error { return New("failed") }()
+	if StackDepth(e1) < 1 || StackDepth(e2) != StackDepth(e1)+1 {
+		t.Fatal()
+	}
+	if e1.(*baseError).StackDepth() != StackDepth(e1) {
+		t.Fatal()
+	}
+	if StackDepth(fmt.Errorf("load: %w", e1)) != StackDepth(e1) {
+		t.Fatal()
+	}
+
+	if (&baseError{}).StackDepth() != 0 || StackDepth(fmt.Errorf("plain")) != 0 || StackDepth(nil) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestCreatedAt(t *testing.T) {
+	before := time.Now()
+	err := Wrap(New("inner"), "outer")
+	after := time.Now()
+
+	if err.CreatedAt().Before(before) || err.CreatedAt().After(after) {
+		t.Fatal()
+	}
+	if created, ok := CreatedAt(err); !ok || !created.Equal(err.CreatedAt()) {
+		t.Fatal()
+	}
+	if _, ok := CreatedAt(fmt.Errorf("plain")); ok {
+		t.Fatal()
+	}
+	if created, ok := CreatedAt(fmt.Errorf("x: %w", err)); !ok || !created.Equal(err.CreatedAt()) {
+		t.Fatal()
+	}
+	if _, ok := CreatedAt(fmt.Errorf("x: %w", fmt.Errorf("y"))); ok {
+		t.Fatal()
+	}
+
+	line := "CREATED: " + err.CreatedAt().UTC().Format(time.RFC3339)
+	if strings.Index(err.Error(), line) == -1 {
+		t.Fatal()
+	}
+}
+
+func TestEqual(t *testing.T) {
+	e1 := NewByCode(404, "not found")
+	e2 := NewByCode(404, "not found")
+	e3 := NewByCode(404, "gone")
+	e4 := NewByCode(500, "not found")
+	plain := fmt.Errorf("not found")
+
+	if !Equal(nil, nil) || !Equal(e1, e2) || Equal(e1, e3) || Equal(e1, e4) {
+		t.Fatal()
+	}
+	if Equal(e1, nil) || Equal(e1, plain) || !Equal(plain, plain) {
+		t.Fatal()
+	}
+
+	if !EqualCode(e1, e3) || EqualCode(e1, e4) || !EqualCode(nil, nil) {
+		t.Fatal()
+	}
+	if !EqualMessage(e1, e4) || EqualMessage(e1, e3) || EqualMessage(e1, nil) {
+		t.Fatal()
+	}
+}
+
+func TestChain(t *testing.T) {
+	base := fmt.Errorf("connection refused")
+	e := Chain(base, "read row", "load user", "handle request")
+
+	if e.Message() != "handle request" {
+		t.Fatal()
+	}
+	if Message(e) != "handle request load user read row connection refused" {
+		t.Fatal(Message(e))
+	}
+	if strings.Index(e.Stack(), "TestChain") == -1 {
+		t.Fatal()
+	}
+
+	inner := e.Inner().(Error).Inner().(Error)
+	if inner.Message() != "read row" || inner.Inner() != base || inner.Stack() != e.Stack() {
+		t.Fatal()
+	}
+
+	if Message(Chain(base)) != "connection refused" {
+		t.Fatal()
+	}
+}
+
+func TestWrapc(t *testing.T) {
+	fields := map[string]interface{}{"request_id": "r-1", "user": 7}
+	e := Wrapc(fmt.Errorf("timeout"), "call backend", fields)
+	fields["user"] = 8
+
+	if v, ok := GetContext(e, "user"); !ok || v != 7 {
+		t.Fatal()
+	}
+	if Message(e) != "call backend timeout" {
+		t.Fatal()
+	}
+	if strings.Index(e.Error(), "call backend\nrequest_id=r-1\nuser=7\ntimeout") == -1 {
+		t.Fatalf("couldn't find context in:\n%s", e.Error())
+	}
+	if strings.Index(e.Stack(), "TestWrapc") == -1 {
+		t.Fatal()
+	}
+}
+
+func stackHelper(skip int) string {
+	stack, _ := StackTraceSkip(skip)
+	return stack
+}
+
+func TestStackTraceSkip(t *testing.T) {
+	first := func(stack string) string {
+		return strings.Split(stack, "\n")[1]
+	}
+
+	if f := first(stackHelper(0)); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.stackHelper(") {
+		t.Fatal(f)
+	}
+	if f := first(stackHelper(1)); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.TestStackTraceSkip(") {
+		t.Fatal(f)
+	}
+	if f := first(stackHelper(-1)); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.stackHelper(") {
+		t.Fatal(f)
+	}
+}
+
+func newHelperError(skip int) Error {
+	return NewSkip(skip, "helper failed")
+}
+
+func TestNewSkip(t *testing.T) {
+	first := func(e Error) string {
+		return strings.Split(e.Stack(), "\n")[1]
+	}
+
+	if f := first(newHelperError(0)); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.newHelperError(") {
+		t.Fatal(f)
+	}
+	e := newHelperError(1)
+	if f := first(e); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.TestNewSkip(") {
+		t.Fatal(f)
+	}
+	if e.Message() != "helper failed" || e.Code() != DefaultErrCode {
+		t.Fatal(e)
+	}
+	if f := first(newHelperError(-1)); !strings.HasPrefix(f, "github.com/uestcer/utils/errors.newHelperError(") {
+		t.Fatal(f)
+	}
+}
+
+func TestGoroutineID(t *testing.T) {
+	err := New("x")
+	id := err.GoroutineID()
+	if id <= 0 {
+		t.Fatal(err.Stack())
+	}
+	if strings.Index(err.Error(), "GOROUTINE: "+strconv.FormatInt(id, 10)) == -1 {
+		t.Fatal(err.Error())
+	}
+
+	ch := make(chan Error)
+	go func() { ch <- New("y") }()
+	if other := <-ch; other.GoroutineID() <= 0 || other.GoroutineID() == id {
+		t.Fatal(other.GoroutineID(), id)
+	}
+
+	if goroutineID("goroutine 42 [running]:\nmain.main()") != 42 ||
+		goroutineID("main.main()") != 0 || goroutineID("") != 0 {
+		t.Fatal()
+	}
+}
+
+func deferLoad(fail bool) (err error) {
+	defer Deferf(&err, "load %s", "config")
+	if fail {
+		return fmt.Errorf("no such file")
+	}
+	return nil
+}
+
+func TestDeferf(t *testing.T) {
+	if err := deferLoad(false); err != nil {
+		t.Fatal(err)
+	}
+
+	err := deferLoad(true)
+	e, ok := err.(Error)
+	if !ok || e.Message() != "load config" || Message(e) != "load config no such file" {
+		t.Fatal(err)
+	}
+	if f := strings.Split(e.Stack(), "\n")[1]; !strings.HasPrefix(f, "github.com/uestcer/utils/errors.deferLoad(") {
+		t.Fatal(f)
+	}
+
+	Deferf(nil, "x")
+}
@@ -0,0 +1,25 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	stderrors "errors"
+)
+
+// This returns the wrapped error, the same as Inner, so the standard
+// errors.Is and errors.As walk the chain of wrapped errors.
+func (e *baseError) Unwrap() error {
+	return e.inner
+}
+
+// This returns the first error in the chain of err assignable to T, walking
+// the chain by Unwrap like the standard errors.As, but without declaring a
+// target variable first.
+// Example: if e, ok := errors.Extract[HTTPError](err); ok { ... }
+func Extract[T Error](err error) (T, bool) {
+	var target T
+	ok := stderrors.As(err, &target)
+	return target, ok
+}
@@ -0,0 +1,49 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"testing"
+)
+
+type httpError struct {
+	*baseError
+	status int
+}
+
+func TestUnwrap(t *testing.T) {
+	inner := fmt.Errorf("inner")
+	err := Wrap(inner, "outer")
+	if !stderrors.Is(err, inner) || stderrors.Unwrap(err) != inner {
+		t.Fatal()
+	}
+	if stderrors.Unwrap(New("x")) != nil {
+		t.Fatal()
+	}
+}
+
+func TestExtract(t *testing.T) {
+	herr := &httpError{New("not found").(*baseError), 404}
+	err := fmt.Errorf("handler: %w", Wrap(herr, "lookup"))
+
+	e, ok := Extract[*httpError](err)
+	if !ok || e.status != 404 || e.Message() != "not found" {
+		t.Fatal(e, ok)
+	}
+
+	// The first Error in the chain.
+	if e, ok := Extract[Error](err); !ok || e.Message() != "lookup" {
+		t.Fatal(e, ok)
+	}
+
+	if e, ok := Extract[*httpError](Wrap(fmt.Errorf("x"), "y")); ok || e != nil {
+		t.Fatal(e, ok)
+	}
+	if _, ok := Extract[Error](nil); ok {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"strings"
+	"time"
+)
+
+// Picks the code of the Error joining errs, see JoinWith.
+// errs has at least one error, and no nil error.
+type CodeStrategy func(errs []error) int
+
+// The code of the first error with a code other than DefaultErrCode.
+// This is the strategy of Join.
+func FirstCode(errs []error) int {
+	for _, err := range errs {
+		if code := innerCode(err); code != DefaultErrCode {
+			return code
+		}
+	}
+	return DefaultErrCode
+}
+
+// The code of the error with the highest severity, the first one if
+// several have it. Errors other than Error have SeverityUnknown.
+func HighestSeverityCode(errs []error) int {
+	var best error
+	highest := Severity(-1)
+	for _, err := range errs {
+		if sev := severityOf(err); sev > highest {
+			best, highest = err, sev
+		}
+	}
+	return innerCode(best)
+}
+
+// Returns the severity of err if it is an Error, otherwise SeverityUnknown.
+func severityOf(err error) Severity {
+	if e, ok := err.(Error); ok {
+		return e.Severity()
+	}
+	return SeverityUnknown
+}
+
+// The code other than DefaultErrCode shared by the most errors, the one
+// occurring first if several are equally common.
+func MostCommonCode(errs []error) int {
+	counts := make(map[int]int)
+	code, most := DefaultErrCode, 0
+	for _, err := range errs {
+		c := innerCode(err)
+		if c == DefaultErrCode {
+			continue
+		}
+		counts[c]++
+		if counts[c] > most {
+			code, most = c, counts[c]
+		}
+	}
+	return code
+}
+
+// This returns nil if all errs are nil, otherwise a single Error whose
+// message joins the messages of the non-nil errors with "; ", whose code
+// is picked by FirstCode, and whose stack trace is the current one.
+// The severity is the highest of the errors with that code.
+// Like the standard errors.Join, the result has an Unwrap() []error method
+// returning the non-nil errors, so errors.Is, errors.As and Extract find
+// them. Inner is nil, the messages are already joined.
+func Join(errs ...error) Error {
+	return join(FirstCode, errs)
+}
+
+// Same as Join, but the code is picked by strategy.
+func JoinWith(strategy CodeStrategy, errs ...error) Error {
+	return join(strategy, errs)
+}
+
+// The Error returned by Join.
+type joinError struct {
+	*baseError
+	errs []error
+}
+
+// This returns the joined errors.
+func (e *joinError) Unwrap() []error {
+	return e.errs
+}
+
+func join(strategy CodeStrategy, errs []error) Error {
+	nonNil := make([]error, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	if len(nonNil) == 0 {
+		return nil
+	}
+
+	code := strategy(nonNil)
+	severity := SeverityUnknown
+	for _, err := range nonNil {
+		if sev := severityOf(err); innerCode(err) == code && sev > severity {
+			severity = sev
+		}
+	}
+
+	stack, context := stackTrace(3)
+	e := &baseError{
+		message:   joinMessages(nonNil),
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      code,
+		severity:  severity,
+	}
+	return &joinError{e, nonNil}
+}
+
+// Join the messages of errs with "; ".
+func joinMessages(errs []error) string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		if _, ok := err.(Error); ok {
+			msgs[i] = Message(err)
+		} else {
+			msgs[i] = err.Error()
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	if Join() != nil || Join(nil, nil) != nil {
+		t.Fatal()
+	}
+
+	err := Join(New("a"), nil, NewByCode(400, "b"), fmt.Errorf("c"), NewByCode(500, "d"))
+	if err.Message() != "a; b; c; d" || err.Code() != 400 {
+		t.Fatal(err.Message(), err.Code())
+	}
+	if !strings.Contains(err.Stack(), "TestJoin") {
+		t.Fatal(err.Stack())
+	}
+
+	if Join(New("a"), fmt.Errorf("b")).Code() != DefaultErrCode {
+		t.Fatal()
+	}
+	if err.Inner() != nil || Message(err) != "a; b; c; d" {
+		t.Fatal(Message(err))
+	}
+}
+
+func TestJoinUnwrap(t *testing.T) {
+	a, b := New("a"), NewByCode(400, "b")
+	base := fmt.Errorf("c")
+	err := Join(a, nil, b, Wrap(base, "wrapped"))
+	if !stderrors.Is(err, a) || !stderrors.Is(err, b) || !stderrors.Is(err, base) {
+		t.Fatal()
+	}
+	if e, ok := Extract[Error](fmt.Errorf("batch: %w", err)); !ok || e != err {
+		t.Fatal()
+	}
+	errs := err.(interface{ Unwrap() []error }).Unwrap()
+	if len(errs) != 3 || errs[0] != a || errs[1] != b {
+		t.Fatal(errs)
+	}
+
+	outer := Wrap(err, "outer")
+	if !strings.Contains(outer.Error(), "a; b; wrapped c") {
+		t.Fatal(outer.Error())
+	}
+}
+
+func TestJoinWith(t *testing.T) {
+	errs := []error{
+		NewFull(400, SeverityWarning, "a"),
+		NewFull(500, SeverityCritical, "b"),
+		NewFull(422, SeverityError, "c"),
+		NewFull(422, SeverityCritical, "d"),
+		fmt.Errorf("e"),
+	}
+
+	if code := JoinWith(FirstCode, errs...).Code(); code != 400 {
+		t.Fatal(code)
+	}
+	if err := JoinWith(HighestSeverityCode, errs...); err.Code() != 500 || err.Severity() != SeverityCritical {
+		t.Fatal(err.Code(), err.Severity())
+	}
+	if err := JoinWith(MostCommonCode, errs...); err.Severity() != SeverityCritical {
+		t.Fatal(err.Severity())
+	}
+	if err := Join(errs...); err.Severity() != SeverityWarning {
+		t.Fatal(err.Severity())
+	}
+	if code := JoinWith(MostCommonCode, errs...).Code(); code != 422 {
+		t.Fatal(code)
+	}
+
+	// Ties are broken by the first occurrence.
+	if code := JoinWith(MostCommonCode, errs[0], errs[1]).Code(); code != 400 {
+		t.Fatal(code)
+	}
+	if code := JoinWith(HighestSeverityCode, fmt.Errorf("x"), New("y")).Code(); code != DefaultErrCode {
+		t.Fatal(code)
+	}
+}
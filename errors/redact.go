@@ -0,0 +1,39 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"regexp"
+	"sync"
+)
+
+// Replacement of the substrings matched by the redactors.
+const RedactedText = "***"
+
+var (
+	redactorsMu sync.RWMutex
+	redactors   []*regexp.Regexp
+)
+
+// Sets the patterns of sensitive substrings, e.g. emails or tokens.
+// The matches are replaced with RedactedText when an error is rendered
+// by Message or Error(), across the whole error chain. The stored
+// messages are not changed, so Error.Message() returns the original text.
+// Passing nil disables redaction.
+func SetRedactors(patterns []*regexp.Regexp) {
+	redactorsMu.Lock()
+	defer redactorsMu.Unlock()
+	redactors = append([]*regexp.Regexp(nil), patterns...)
+}
+
+// Masks the substrings of s matched by the redactors.
+func redact(s string) string {
+	redactorsMu.RLock()
+	defer redactorsMu.RUnlock()
+	for _, re := range redactors {
+		s = re.ReplaceAllString(s, RedactedText)
+	}
+	return s
+}
@@ -0,0 +1,41 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	SetRedactors([]*regexp.Regexp{
+		regexp.MustCompile(`token=\w+`),
+		regexp.MustCompile(`[\w.]+@[\w.]+`),
+	})
+	defer SetRedactors(nil)
+
+	inner := fmt.Errorf("auth failed: token=abc123")
+	middle := Wrap(inner, "user li@example.com")
+	outer := Wrap(middle, "request failed")
+
+	errorStr := outer.Error()
+	if strings.Index(errorStr, "abc123") != -1 || strings.Index(errorStr, "li@example.com") != -1 {
+		t.Fatalf("sensitive text in:\n%s", errorStr)
+	}
+	if strings.Index(errorStr, "auth failed: ***") == -1 || strings.Index(errorStr, "user ***") == -1 {
+		t.Fatalf("couldn't find redacted text in:\n%s", errorStr)
+	}
+
+	if Message(outer) != "request failed user *** auth failed: ***" {
+		t.Fatal(Message(outer))
+	}
+
+	// The stored message is intact.
+	if middle.Message() != "user li@example.com" {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// The severity of an error, higher is more severe.
+type Severity int
+
+// The severity is SeverityUnknown if Error was not created by NewFull.
+const (
+	SeverityUnknown Severity = iota
+	SeverityDebug
+	SeverityInfo
+	SeverityWarning
+	SeverityError
+	SeverityCritical
+)
+
+var severityNames = []string{"unknown", "debug", "info", "warning", "error", "critical"}
+
+// This returns the lower case name of the severity, e.g. "warning".
+func (s Severity) String() string {
+	if s >= 0 && int(s) < len(severityNames) {
+		return severityNames[s]
+	}
+	return fmt.Sprintf("Severity(%d)", int(s))
+}
+
+// This returns a new baseError initialized with the given error code, severity,
+// fmt.Printf-style message and the current stack trace.
+func NewFull(code int, sev Severity, format string, args ...interface{}) Error {
+	stack, context := StackTrace()
+	return &baseError{
+		message:   fmt.Sprintf(format, args...),
+		format:    format,
+		args:      args,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      code,
+		severity:  sev,
+	}
+}
+
+// The JSON form of baseError.
+type jsonError struct {
+	Message   string                 `json:"message"`
+	Code      int                    `json:"code"`
+	Severity  string                 `json:"severity"`
+	Fields    map[string]interface{} `json:"fields,omitempty"`
+	Inner     string                 `json:"inner,omitempty"`
+	CreatedAt time.Time              `json:"created_at"`
+	Stack     string                 `json:"stack"`
+}
+
+// Implements json.Marshaler. The messages and fields are redacted,
+// see SetRedactors.
+func (e *baseError) MarshalJSON() ([]byte, error) {
+	j := jsonError{
+		Message:   redact(e.translatedMessage()),
+		Code:      e.code,
+		Severity:  e.severity.String(),
+		Fields:    e.redactedFields(),
+		CreatedAt: e.createdAt.UTC(),
+		Stack:     e.stack,
+	}
+	if e.inner != nil {
+		j.Inner = redact(e.inner.Error())
+		if inner, ok := e.inner.(Error); ok {
+			j.Inner = Message(inner)
+		}
+	}
+	return json.Marshal(j)
+}
+
+// This returns the fields with the values redacted as in the field lines of
+// DefaultError, i.e. the "key=value" line is redacted. A value left intact is
+// kept as is, a redacted one becomes the redacted text of the line without
+// the "key=" prefix.
+func (e *baseError) redactedFields() map[string]interface{} {
+	if e.fields == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(e.fields))
+	for k, v := range e.fields {
+		line := fmt.Sprintf("%s=%v", k, v)
+		if r := redact(line); r != line {
+			fields[k] = strings.TrimPrefix(r, k+"=")
+		} else {
+			fields[k] = v
+		}
+	}
+	return fields
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestSeverityString(t *testing.T) {
+	if SeverityWarning.String() != "warning" || SeverityUnknown.String() != "unknown" ||
+		Severity(100).String() != "Severity(100)" {
+		t.Fatal()
+	}
+}
+
+func TestNewFull(t *testing.T) {
+	err := NewFull(404, SeverityWarning, "user %d not found", 7)
+	if err.Code() != 404 || err.Severity() != SeverityWarning || err.Message() != "user 7 not found" {
+		t.Fatal(err)
+	}
+	if New("plain").Severity() != SeverityUnknown {
+		t.Fatal()
+	}
+}
+
+func TestMarshalJSON(t *testing.T) {
+	err := Wrapc(NewFull(500, SeverityCritical, "disk full"), "save failed", map[string]interface{}{"id": 42})
+	err.SetCode(501)
+
+	b, e := json.Marshal(err)
+	if e != nil {
+		t.Fatal(e)
+	}
+	var m map[string]interface{}
+	if e := json.Unmarshal(b, &m); e != nil {
+		t.Fatal(e)
+	}
+	if m["message"] != "save failed" || m["code"] != float64(501) || m["severity"] != "unknown" ||
+		m["inner"] != "disk full" || m["fields"].(map[string]interface{})["id"] != float64(42) {
+		t.Fatal(string(b))
+	}
+	if !strings.Contains(m["stack"].(string), "TestMarshalJSON") || m["created_at"] == "" {
+		t.Fatal(string(b))
+	}
+
+	b, _ = json.Marshal(NewFull(1, SeverityInfo, "x"))
+	if !strings.Contains(string(b), `"severity":"info"`) || strings.Contains(string(b), `"inner"`) {
+		t.Fatal(string(b))
+	}
+}
+
+func TestMarshalJSONRedacted(t *testing.T) {
+	SetRedactors([]*regexp.Regexp{
+		regexp.MustCompile(`token=\w+`),
+		regexp.MustCompile(`[\w.]+@[\w.]+`),
+	})
+	defer SetRedactors(nil)
+
+	fields := map[string]interface{}{"token": "abc123", "user": "li@example.com", "id": 42}
+	b, e := json.Marshal(Wrapc(fmt.Errorf("denied"), "login li@example.com", fields))
+	if e != nil {
+		t.Fatal(e)
+	}
+	if strings.Contains(string(b), "abc123") || strings.Contains(string(b), "li@example.com") {
+		t.Fatal(string(b))
+	}
+
+	var m map[string]interface{}
+	if e := json.Unmarshal(b, &m); e != nil {
+		t.Fatal(e)
+	}
+	f := m["fields"].(map[string]interface{})
+	if f["token"] != "***" || f["user"] != "***" || f["id"] != float64(42) || m["message"] != "login ***" {
+		t.Fatal(string(b))
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	stackFilterMu sync.RWMutex
+	skipPackages  []string
+	stackFilter   func(frame string) bool
+)
+
+// Removes the frames of functions in package pkg from the stack traces
+// captured afterwards, e.g. AddSkipPackage("testing").
+func AddSkipPackage(pkg string) {
+	stackFilterMu.Lock()
+	defer stackFilterMu.Unlock()
+	skipPackages = append(skipPackages, pkg)
+}
+
+// Sets a function deciding which frames are kept in the stack traces
+// captured afterwards. A frame is the function line and the indented
+// file line, e.g. "main.main()\n\t/src/main.go:12 +0x25".
+// f returns false to remove the frame. Passing nil keeps all frames.
+func SetStackFilter(f func(frame string) bool) {
+	stackFilterMu.Lock()
+	defer stackFilterMu.Unlock()
+	stackFilter = f
+}
+
+// Removes the frames rejected by the skipped packages and the stack filter
+// from a stack trace. The goroutine header line is always kept.
+// The filter runs without holding stackFilterMu, so it may call
+// SetStackFilter or AddSkipPackage, or create errors.
+func filterStack(stack string) string {
+	stackFilterMu.RLock()
+	pkgs, filter := skipPackages, stackFilter
+	stackFilterMu.RUnlock()
+	if len(pkgs) == 0 && filter == nil {
+		return stack
+	}
+
+	lines := strings.Split(stack, "\n")
+	result := lines[:1]
+	for i := 1; i < len(lines); i++ {
+		if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "\t") {
+			if keepFrame(pkgs, filter, lines[i], lines[i+1]) {
+				result = append(result, lines[i], lines[i+1])
+			}
+			i++
+			continue
+		}
+		result = append(result, lines[i])
+	}
+	return strings.Join(result, "\n")
+}
+
+// Checks a frame against the skipped packages pkgs and the stack filter.
+func keepFrame(pkgs []string, filter func(frame string) bool, funcLine, fileLine string) bool {
+	name := strings.TrimPrefix(funcLine, "created by ")
+	for _, pkg := range pkgs {
+		if strings.HasPrefix(name, pkg+".") {
+			return false
+		}
+	}
+	return filter == nil || filter(funcLine+"\n"+fileLine)
+}
@@ -0,0 +1,73 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func resetStackFilter() {
+	skipPackages = nil
+	SetStackFilter(nil)
+}
+
+func TestAddSkipPackage(t *testing.T) {
+	if strings.Index(New("failed").Stack(), "testing.tRunner") == -1 {
+		t.Fatal()
+	}
+
+	AddSkipPackage("testing")
+	defer resetStackFilter()
+
+	e := New("failed")
+	if strings.Index(e.Stack(), "testing.tRunner") != -1 {
+		t.Fatalf("testing frames in:\n%s", e.Stack())
+	}
+	if strings.Index(e.Stack(), "TestAddSkipPackage") == -1 || !strings.HasPrefix(e.Stack(), "goroutine ") {
+		t.Fatalf("frames missing in:\n%s", e.Stack())
+	}
+	if StackDepth(e) != 1 {
+		t.Fatal(StackDepth(e))
+	}
+}
+
+func TestSetStackFilter(t *testing.T) {
+	SetStackFilter(func(frame string) bool {
+		return strings.Index(frame, "TestSetStackFilter") == -1
+	})
+	defer resetStackFilter()
+
+	e := New("failed")
+	if strings.Index(e.Stack(), "TestSetStackFilter") != -1 ||
+		strings.Index(e.Stack(), "testing.tRunner") == -1 {
+		t.Fatalf("unexpected frames in:\n%s", e.Stack())
+	}
+}
+
+func TestStackFilterReentrant(t *testing.T) {
+	defer resetStackFilter()
+
+	calls := 0
+	SetStackFilter(func(frame string) bool {
+		if calls++; calls == 1 {
+			AddSkipPackage("testing")
+			SetStackFilter(nil)
+		}
+		return true
+	})
+
+	done := make(chan Error)
+	go func() { done <- New("failed") }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("deadlock")
+	}
+	if strings.Index(New("failed").Stack(), "testing.tRunner") != -1 {
+		t.Fatal()
+	}
+}
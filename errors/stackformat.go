@@ -0,0 +1,113 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// Built-in templates for FormatStack.
+// {func} is the function name without arguments, {file} the full file path,
+// {base} the file name without directory, and {line} the line number.
+const (
+	// e.g. "main.main (main.go:12)"
+	StackShortFormat = "{func} ({base}:{line})"
+
+	// e.g. "main.main (/src/main.go:12)"
+	StackLongFormat = "{func} ({file}:{line})"
+)
+
+// A frame parsed from a stack trace captured by runtime.Stack.
+type stackFrame struct {
+	function string
+	file     string
+	line     string
+}
+
+// This returns the stack trace rendered by FormatStack with StackShortFormat.
+func (e *baseError) StackShort() string {
+	return e.FormatStack(StackShortFormat)
+}
+
+// This returns the stack trace with one line per frame rendered by tmpl,
+// without the goroutine header and the function arguments.
+func (e *baseError) FormatStack(tmpl string) string {
+	return formatStack(e.stack, tmpl)
+}
+
+// This returns the stack trace of the first Error in the chain of err with
+// one compact "func (file:line)" line per frame, see StackShortFormat.
+// "" if there is no Error in the chain.
+func StackShort(err error) string {
+	return FormatStack(err, StackShortFormat)
+}
+
+// This returns the stack trace of the first Error in the chain of err with
+// one line per frame rendered by tmpl, see StackShortFormat for the
+// placeholders. "" if there is no Error in the chain.
+func FormatStack(err error, tmpl string) string {
+	if e, ok := Extract[Error](err); ok {
+		return formatStack(e.Stack(), tmpl)
+	}
+	return ""
+}
+
+func formatStack(stack, tmpl string) string {
+	frames := parseStack(stack)
+	lines := make([]string, len(frames))
+	for i, f := range frames {
+		lines[i] = strings.NewReplacer(
+			"{func}", f.function,
+			"{file}", f.file,
+			"{base}", filepath.Base(f.file),
+			"{line}", f.line,
+		).Replace(tmpl)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Parse the function line and the indented file line of every frame,
+// e.g. "main.main()\n\t/src/main.go:12 +0x25". Other lines are ignored.
+func parseStack(stack string) []stackFrame {
+	lines := strings.Split(stack, "\n")
+	frames := []stackFrame{}
+	for i := 0; i+1 < len(lines); i++ {
+		if !strings.HasPrefix(lines[i+1], "\t") || strings.HasPrefix(lines[i], "\t") {
+			continue
+		}
+
+		f := stackFrame{function: funcName(lines[i])}
+		fileLine := strings.TrimPrefix(lines[i+1], "\t")
+		if j := strings.LastIndex(fileLine, " +"); j != -1 {
+			fileLine = fileLine[:j]
+		}
+		f.file = fileLine
+		if j := strings.LastIndex(fileLine, ":"); j != -1 {
+			f.file, f.line = fileLine[:j], fileLine[j+1:]
+		}
+		frames = append(frames, f)
+		i++
+	}
+	return frames
+}
+
+// Strip the arguments of a function line, e.g. "main.f(0x1, ...)" to "main.f",
+// and the goroutine of a creator line, e.g. "created by main.main in goroutine 1".
+func funcName(line string) string {
+	if strings.HasPrefix(line, "created by ") {
+		line = strings.TrimPrefix(line, "created by ")
+		if j := strings.Index(line, " in goroutine "); j != -1 {
+			line = line[:j]
+		}
+		return line
+	}
+	if strings.HasSuffix(line, ")") {
+		if j := strings.LastIndex(line, "("); j > 0 {
+			line = line[:j]
+		}
+	}
+	return line
+}
@@ -0,0 +1,52 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestStackShort(t *testing.T) {
+	err := New("x")
+	lines := strings.Split(StackShort(err), "\n")
+	if len(lines) != StackDepth(err) {
+		t.Fatal(StackShort(err))
+	}
+	if !strings.HasPrefix(lines[0], "github.com/uestcer/utils/errors.TestStackShort (stackformat_test.go:") ||
+		!strings.HasSuffix(lines[0], ")") {
+		t.Fatal(lines[0])
+	}
+	if err.(*baseError).StackShort() != StackShort(err) ||
+		err.(*baseError).FormatStack(StackLongFormat) != FormatStack(fmt.Errorf("x: %w", err), StackLongFormat) {
+		t.Fatal()
+	}
+	if StackShort(fmt.Errorf("plain")) != "" {
+		t.Fatal()
+	}
+}
+
+func TestFormatStack(t *testing.T) {
+	stack := "goroutine 1 [running]:\n" +
+		"main.(*T).f(0xc000010000, {0x1, 0x2})\n" +
+		"\t/src/main.go:12 +0x25\n" +
+		"main.main()\n" +
+		"\t/src/main.go:20 +0x1d\n" +
+		"created by main.start in goroutine 1\n" +
+		"\t/src/start.go:5 +0x3a"
+
+	r := formatStack(stack, StackShortFormat)
+	if r != "main.(*T).f (main.go:12)\nmain.main (main.go:20)\nmain.start (start.go:5)" {
+		t.Fatal(r)
+	}
+	r = formatStack(stack, "{line} {file}")
+	if r != "12 /src/main.go\n20 /src/main.go\n5 /src/start.go" {
+		t.Fatal(r)
+	}
+	if formatStack("", StackLongFormat) != "" {
+		t.Fatal()
+	}
+}
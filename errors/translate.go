@@ -0,0 +1,96 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Translator produces localised error messages.
+type Translator interface {
+
+	// This returns the message of the error code, formatted with args.
+	// Return defaultMsg formatted with args if code is unknown.
+	Translate(code int, defaultMsg string, args ...interface{}) string
+}
+
+var (
+	translatorMu sync.RWMutex
+	translator   Translator
+)
+
+// Sets the translator used by Translatef, DefaultError and MarshalJSON.
+// Passing nil disables translation.
+func SetGlobalTranslator(t Translator) {
+	translatorMu.Lock()
+	defer translatorMu.Unlock()
+	translator = t
+}
+
+// This returns the global translator, nil if not set.
+func globalTranslator() Translator {
+	translatorMu.RLock()
+	defer translatorMu.RUnlock()
+	return translator
+}
+
+// Same as NewfByCode, but the message is produced by the global translator
+// from the error code, format is the default message.
+// Message returns the text translated here, DefaultError and MarshalJSON
+// translate again with the translator set at that time.
+func Translatef(code int, format string, args ...interface{}) Error {
+	var msg string
+	if t := globalTranslator(); t != nil {
+		msg = t.Translate(code, format, args...)
+	} else {
+		msg = fmt.Sprintf(format, args...)
+	}
+
+	stack, context := StackTrace()
+	return &baseError{
+		message:   msg,
+		stack:     stack,
+		context:   context,
+		createdAt: time.Now(),
+		code:      code,
+		format:    format,
+		args:      args,
+	}
+}
+
+// This returns the message translated by the global translator from the
+// error code, with the format and args the error was created with.
+// Return the message as is if no translator is set.
+func (e *baseError) translatedMessage() string {
+	t := globalTranslator()
+	if t == nil || e.message == "" {
+		return e.message
+	}
+	if e.format == "" {
+		return t.Translate(e.code, e.message)
+	}
+	return t.Translate(e.code, e.format, e.args...)
+}
+
+// Creates a Translator looking up the messages in m by error code.
+// The messages are fmt.Printf-style formats.
+func NewMapTranslator(m map[int]string) Translator {
+	return mapTranslator(m)
+}
+
+type mapTranslator map[int]string
+
+func (t mapTranslator) Translate(code int, defaultMsg string, args ...interface{}) string {
+	msg, ok := t[code]
+	if !ok {
+		msg = defaultMsg
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
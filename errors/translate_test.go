@@ -0,0 +1,61 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMapTranslator(t *testing.T) {
+	tr := NewMapTranslator(map[int]string{404: "%s introuvable"})
+	if tr.Translate(404, "%s not found", "user") != "user introuvable" ||
+		tr.Translate(500, "%s failed", "user") != "user failed" ||
+		tr.Translate(500, "100% failed") != "100% failed" {
+		t.Fatal()
+	}
+}
+
+func TestTranslatef(t *testing.T) {
+	e1 := Translatef(404, "%s not found", "user")
+	if e1.Message() != "user not found" || e1.Code() != 404 {
+		t.Fatal()
+	}
+
+	SetGlobalTranslator(NewMapTranslator(map[int]string{
+		404: "%s introuvable",
+		500: "erreur interne",
+	}))
+	defer SetGlobalTranslator(nil)
+
+	e2 := Translatef(404, "%s not found", "user")
+	if e2.Message() != "user introuvable" {
+		t.Fatal()
+	}
+	if strings.Index(e2.Error(), "user introuvable") == -1 {
+		t.Fatal()
+	}
+
+	e3 := WrapByCode(500, New("disk full"), "internal error")
+	errorStr := e3.Error()
+	if strings.Index(errorStr, "erreur interne") == -1 || strings.Index(errorStr, "disk full") == -1 {
+		t.Fatalf("couldn't find translated message in:\n%s", errorStr)
+	}
+
+	// The format args are kept, and errors created before the translator
+	// was set are translated too.
+	SetGlobalTranslator(nil)
+	e4 := NewfByCode(404, "user %d not found", 7)
+	SetGlobalTranslator(NewMapTranslator(map[int]string{404: "Benutzer %d nicht gefunden"}))
+	if e4.Message() != "user 7 not found" || strings.Index(e4.Error(), "Benutzer 7 nicht gefunden") == -1 {
+		t.Fatal(e4.Error())
+	}
+	if b, err := e4.(*baseError).MarshalJSON(); err != nil || strings.Index(string(b), "Benutzer 7 nicht gefunden") == -1 {
+		t.Fatal(string(b), err)
+	}
+	if e5 := NewfByCode(404, "user %d not found", 8); strings.Index(e5.Error(), "Benutzer 8 nicht gefunden") == -1 {
+		t.Fatal(e5.Error())
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// An Error mapping field names to validation messages, e.g. for the
+// response of an API validating a form. The code is DefaultErrCode, the
+// stack trace and creation time are captured by the first Add.
+// ValidationError is not thread safe.
+type ValidationError interface {
+	Error
+
+	// This appends msg to the messages of field.
+	Add(field, msg string)
+
+	// This returns true if any message was added.
+	HasErrors() bool
+
+	// This returns a copy of the messages by field.
+	Fields() map[string][]string
+}
+
+type validationError struct {
+	baseError
+	messages map[string][]string
+
+	// Field names in the order of their first message.
+	order []string
+}
+
+// This returns an empty ValidationError.
+func NewValidation() ValidationError {
+	return &validationError{
+		baseError: baseError{code: DefaultErrCode},
+		messages:  make(map[string][]string),
+	}
+}
+
+func (e *validationError) Add(field, msg string) {
+	if len(e.order) == 0 {
+		e.stack, e.context = stackTrace(2)
+		e.createdAt = time.Now()
+	}
+	if _, ok := e.messages[field]; !ok {
+		e.order = append(e.order, field)
+	}
+	e.messages[field] = append(e.messages[field], msg)
+}
+
+func (e *validationError) HasErrors() bool {
+	return len(e.order) > 0
+}
+
+func (e *validationError) Fields() map[string][]string {
+	fields := make(map[string][]string, len(e.messages))
+	for k, v := range e.messages {
+		fields[k] = append([]string(nil), v...)
+	}
+	return fields
+}
+
+// This returns the messages in the order of their fields' first message,
+// e.g. "validation failed: name: required; age: too small, not a number".
+func (e *validationError) Message() string {
+	msgs := make([]string, len(e.order))
+	for i, field := range e.order {
+		msgs[i] = field + ": " + strings.Join(e.messages[field], ", ")
+	}
+	return "validation failed: " + strings.Join(msgs, "; ")
+}
+
+func (e *validationError) Error() string {
+	return DefaultError(e)
+}
+
+// Implements json.Marshaler as {field: [messages]}.
+// The messages are redacted, see SetRedactors.
+func (e *validationError) MarshalJSON() ([]byte, error) {
+	fields := e.Fields()
+	for _, msgs := range fields {
+		for i := range msgs {
+			msgs[i] = redact(msgs[i])
+		}
+	}
+	return json.Marshal(fields)
+}
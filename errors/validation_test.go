@@ -0,0 +1,55 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+	v := NewValidation()
+	if v.HasErrors() || v.Stack() != "" || len(v.Fields()) != 0 {
+		t.Fatal()
+	}
+
+	v.Add("name", "required")
+	v.Add("age", "too small")
+	v.Add("age", "not a number")
+	if !v.HasErrors() || v.Code() != DefaultErrCode {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(v.Fields(), map[string][]string{
+		"name": {"required"},
+		"age":  {"too small", "not a number"},
+	}) {
+		t.Fatal(v.Fields())
+	}
+
+	msg := "validation failed: name: required; age: too small, not a number"
+	if v.Message() != msg || Message(v) != msg || !strings.Contains(v.Error(), msg) {
+		t.Fatal(v.Error())
+	}
+	if !strings.Contains(strings.Split(v.Stack(), "\n")[1], "TestValidation") || v.CreatedAt().IsZero() {
+		t.Fatal(v.Stack())
+	}
+
+	// Fields returns a copy.
+	v.Fields()["name"][0] = "changed"
+	if v.Fields()["name"][0] != "required" {
+		t.Fatal()
+	}
+}
+
+func TestValidationJSON(t *testing.T) {
+	v := NewValidation()
+	v.Add("email", "invalid")
+	b, err := json.Marshal(v)
+	if err != nil || string(b) != `{"email":["invalid"]}` {
+		t.Fatal(string(b), err)
+	}
+}
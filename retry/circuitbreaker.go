@@ -0,0 +1,201 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Default values of the circuit breaker options.
+const (
+	DefaultCBMaxFailures      = 5
+	DefaultCBTimeout          = time.Minute
+	DefaultCBSuccessThreshold = 1
+)
+
+// Returned by CircuitBreaker.Execute when the circuit is open.
+var ErrCircuitOpen = errors.New("retry: circuit breaker is open")
+
+// State of a CircuitBreaker.
+type CBState int
+
+const (
+	// Calls are allowed, failures are counted.
+	CBClosed CBState = iota
+	// Calls are rejected until the timeout expires.
+	CBOpen
+	// Calls are allowed on trial, a failure opens the circuit again.
+	CBHalfOpen
+)
+
+func (s CBState) String() string {
+	switch s {
+	case CBClosed:
+		return "closed"
+	case CBOpen:
+		return "open"
+	case CBHalfOpen:
+		return "half-open"
+	default:
+		return "unknown"
+	}
+}
+
+// CBOption configures a CircuitBreaker.
+type CBOption func(*CircuitBreaker)
+
+// Open the circuit after n consecutive failures.
+func CBMaxFailures(n int) CBOption {
+	return func(cb *CircuitBreaker) {
+		cb.maxFailures = n
+	}
+}
+
+// Set how long the circuit stays open before allowing trial calls.
+func CBTimeout(d time.Duration) CBOption {
+	return func(cb *CircuitBreaker) {
+		cb.timeout = d
+	}
+}
+
+// Set the number of successful trial calls needed to close the circuit.
+func CBSuccessThreshold(n int) CBOption {
+	return func(cb *CircuitBreaker) {
+		cb.successThreshold = n
+	}
+}
+
+// CircuitBreaker stops calling a failing function for a while, so that
+// it has a chance to recover. It is safe for concurrent use.
+type CircuitBreaker struct {
+	maxFailures      int
+	timeout          time.Duration
+	successThreshold int
+
+	mu        sync.Mutex
+	state     CBState
+	failures  int
+	successes int
+	openedAt  time.Time
+	onChange  func(from, to CBState)
+	now       func() time.Time
+}
+
+// Create a new closed circuit breaker.
+func NewCircuitBreaker(opts ...CBOption) *CircuitBreaker {
+	cb := &CircuitBreaker{
+		maxFailures:      DefaultCBMaxFailures,
+		timeout:          DefaultCBTimeout,
+		successThreshold: DefaultCBSuccessThreshold,
+		state:            CBClosed,
+		now:              time.Now,
+	}
+	for _, opt := range opts {
+		opt(cb)
+	}
+	return cb
+}
+
+// Call f if the circuit allows it, and record its result.
+// Return ErrCircuitOpen without calling f if the circuit is open.
+func (cb *CircuitBreaker) Execute(f func() error) error {
+	cb.mu.Lock()
+	state, notify := cb.currentState()
+	cb.mu.Unlock()
+	notify()
+
+	if state == CBOpen {
+		return ErrCircuitOpen
+	}
+
+	err := f()
+
+	cb.mu.Lock()
+	notify = cb.record(state, err)
+	cb.mu.Unlock()
+	notify()
+	return err
+}
+
+// This returns the current state.
+func (cb *CircuitBreaker) State() CBState {
+	cb.mu.Lock()
+	state, notify := cb.currentState()
+	cb.mu.Unlock()
+	notify()
+	return state
+}
+
+// Close the circuit and clear the counters.
+func (cb *CircuitBreaker) Reset() {
+	cb.mu.Lock()
+	notify := cb.setState(CBClosed)
+	cb.mu.Unlock()
+	notify()
+}
+
+// Set a function invoked on every state transition.
+// It is called without holding the breaker's lock.
+func (cb *CircuitBreaker) OnStateChange(f func(from, to CBState)) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	cb.onChange = f
+}
+
+// Return the state, moving from open to half-open if the timeout expired.
+// NOTE: cb.mu must be held.
+func (cb *CircuitBreaker) currentState() (CBState, func()) {
+	if cb.state == CBOpen && cb.now().Sub(cb.openedAt) >= cb.timeout {
+		return CBHalfOpen, cb.setState(CBHalfOpen)
+	}
+	return cb.state, func() {}
+}
+
+// Record the result of a call made in state.
+// NOTE: cb.mu must be held.
+func (cb *CircuitBreaker) record(state CBState, err error) func() {
+	if state != cb.state {
+		// The state changed during the call, the result is stale.
+		return func() {}
+	}
+
+	switch {
+	case err != nil && state == CBHalfOpen:
+		return cb.setState(CBOpen)
+	case err != nil:
+		cb.failures++
+		if cb.failures >= cb.maxFailures {
+			return cb.setState(CBOpen)
+		}
+	case state == CBHalfOpen:
+		cb.successes++
+		if cb.successes >= cb.successThreshold {
+			return cb.setState(CBClosed)
+		}
+	default:
+		cb.failures = 0
+	}
+	return func() {}
+}
+
+// Move to state to, and return a function notifying the transition.
+// NOTE: cb.mu must be held.
+func (cb *CircuitBreaker) setState(to CBState) func() {
+	from := cb.state
+	cb.state = to
+	cb.failures = 0
+	cb.successes = 0
+	if to == CBOpen {
+		cb.openedAt = cb.now()
+	}
+
+	f := cb.onChange
+	if from == to || f == nil {
+		return func() {}
+	}
+	return func() { f(from, to) }
+}
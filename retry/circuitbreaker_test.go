@@ -0,0 +1,142 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"testing"
+	"time"
+)
+
+type fakeClock struct {
+	t time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	return c.t
+}
+
+func newTestBreaker(clock *fakeClock, opts ...CBOption) *CircuitBreaker {
+	cb := NewCircuitBreaker(opts...)
+	cb.now = clock.now
+	return cb
+}
+
+func fail() error    { return errFail }
+func succeed() error { return nil }
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	cb := newTestBreaker(&fakeClock{}, CBMaxFailures(3))
+
+	for i := 0; i < 2; i++ {
+		if cb.Execute(fail) != errFail || cb.State() != CBClosed {
+			t.Fatal()
+		}
+	}
+	if cb.Execute(fail) != errFail || cb.State() != CBOpen {
+		t.Fatal()
+	}
+
+	calls := 0
+	err := cb.Execute(func() error { calls++; return nil })
+	if err != ErrCircuitOpen || calls != 0 {
+		t.Fatal()
+	}
+}
+
+func TestCircuitBreakerSuccessResetsFailures(t *testing.T) {
+	cb := newTestBreaker(&fakeClock{}, CBMaxFailures(2))
+	cb.Execute(fail)
+	cb.Execute(succeed)
+	cb.Execute(fail)
+	if cb.State() != CBClosed {
+		t.Fatal()
+	}
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	cb := newTestBreaker(clock, CBMaxFailures(1), CBTimeout(time.Second),
+		CBSuccessThreshold(2))
+
+	cb.Execute(fail)
+	clock.t = clock.t.Add(999 * time.Millisecond)
+	if cb.State() != CBOpen {
+		t.Fatal()
+	}
+	clock.t = clock.t.Add(time.Millisecond)
+	if cb.State() != CBHalfOpen {
+		t.Fatal()
+	}
+
+	// Half-open to closed after enough successes.
+	if cb.Execute(succeed) != nil || cb.State() != CBHalfOpen {
+		t.Fatal()
+	}
+	if cb.Execute(succeed) != nil || cb.State() != CBClosed {
+		t.Fatal()
+	}
+
+	// Half-open to open on a failure.
+	cb.Execute(fail)
+	clock.t = clock.t.Add(time.Second)
+	if cb.State() != CBHalfOpen {
+		t.Fatal()
+	}
+	if cb.Execute(fail) != errFail || cb.State() != CBOpen {
+		t.Fatal()
+	}
+}
+
+func TestCircuitBreakerReset(t *testing.T) {
+	cb := newTestBreaker(&fakeClock{}, CBMaxFailures(1))
+	cb.Execute(fail)
+	cb.Reset()
+	if cb.State() != CBClosed || cb.Execute(succeed) != nil {
+		t.Fatal()
+	}
+}
+
+func TestCircuitBreakerOnStateChange(t *testing.T) {
+	clock := &fakeClock{time.Unix(0, 0)}
+	cb := newTestBreaker(clock, CBMaxFailures(1), CBTimeout(time.Second))
+
+	transitions := []CBState{}
+	cb.OnStateChange(func(from, to CBState) {
+		// The lock is not held, so the callback may call back in.
+		if cb.State() != to {
+			t.Fatal()
+		}
+		transitions = append(transitions, from, to)
+	})
+
+	cb.Execute(fail)
+	clock.t = clock.t.Add(time.Second)
+	cb.Execute(succeed)
+	cb.Execute(fail)
+	cb.Reset()
+
+	expected := []CBState{
+		CBClosed, CBOpen,
+		CBOpen, CBHalfOpen,
+		CBHalfOpen, CBClosed,
+		CBClosed, CBOpen,
+		CBOpen, CBClosed,
+	}
+	if len(transitions) != len(expected) {
+		t.Fatal(transitions)
+	}
+	for i := range expected {
+		if transitions[i] != expected[i] {
+			t.Fatal(transitions)
+		}
+	}
+}
+
+func TestCBStateString(t *testing.T) {
+	if CBClosed.String() != "closed" || CBOpen.String() != "open" ||
+		CBHalfOpen.String() != "half-open" {
+		t.Fatal()
+	}
+}
@@ -0,0 +1,186 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+// Package retry calls a function repeatedly until it succeeds,
+// waiting with exponential backoff between the attempts.
+package retry
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Default values of the retry options.
+const (
+	DefaultMaxAttempts  = 3
+	DefaultInitialDelay = 100 * time.Millisecond
+	DefaultMaxDelay     = 10 * time.Second
+	DefaultMultiplier   = 2.0
+)
+
+// Option configures the behavior of Do.
+type Option func(*options)
+
+type options struct {
+	maxAttempts  int
+	initialDelay time.Duration
+	maxDelay     time.Duration
+	multiplier   float64
+	jitter       float64
+	onRetry      func(attempt int, err error)
+	retryIf      func(err error) bool
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		maxAttempts:  DefaultMaxAttempts,
+		initialDelay: DefaultInitialDelay,
+		maxDelay:     DefaultMaxDelay,
+		multiplier:   DefaultMultiplier,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Set the max number of calls, including the first one.
+// A value less than 1 is treated as 1.
+func MaxAttempts(n int) Option {
+	return func(o *options) {
+		if n < 1 {
+			n = 1
+		}
+		o.maxAttempts = n
+	}
+}
+
+// Set the delay before the first retry.
+func InitialDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.initialDelay = d
+	}
+}
+
+// Set the upper bound of the delay between two attempts.
+func MaxDelay(d time.Duration) Option {
+	return func(o *options) {
+		o.maxDelay = d
+	}
+}
+
+// Set the factor the delay grows by after every retry.
+func Multiplier(m float64) Option {
+	return func(o *options) {
+		o.multiplier = m
+	}
+}
+
+// Randomise every delay by ±factor of its value, e.g. 0.1 for ±10%.
+func Jitter(factor float64) Option {
+	return func(o *options) {
+		o.jitter = factor
+	}
+}
+
+// Set a function invoked before every retry, with the number of the
+// failed attempt (starting from 1) and its error.
+func OnRetry(f func(attempt int, err error)) Option {
+	return func(o *options) {
+		o.onRetry = f
+	}
+}
+
+// Set a function deciding whether an error should be retried.
+// By default all errors are retried.
+func RetryIf(f func(err error) bool) Option {
+	return func(o *options) {
+		o.retryIf = f
+	}
+}
+
+// Call f until it returns nil, the attempts are exhausted, or f returns
+// an error rejected by RetryIf. Return the last error of f.
+// Stop immediately and return ctx.Err() if ctx is cancelled.
+func Do(ctx context.Context, f func() error, opts ...Option) error {
+	o := newOptions(opts)
+
+	delay := o.initialDelay
+	for attempt := 1; ; attempt++ {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		err := f()
+		if err == nil {
+			return nil
+		}
+		if attempt >= o.maxAttempts || (o.retryIf != nil && !o.retryIf(err)) {
+			return err
+		}
+		if o.onRetry != nil {
+			o.onRetry(attempt, err)
+		}
+
+		if err := sleep(ctx, o.jittered(delay)); err != nil {
+			return err
+		}
+		delay = o.next(delay)
+	}
+}
+
+// Same as Do, but for a function returning a result.
+// Return the result and error of the last call of f.
+func DoResult[T any](ctx context.Context, f func() (T, error), opts ...Option) (T, error) {
+	var result T
+	err := Do(ctx, func() error {
+		var err error
+		result, err = f()
+		return err
+	}, opts...)
+	return result, err
+}
+
+// Same as DoResult, for callers not using type parameters.
+func DoValue(ctx context.Context, f func() (interface{}, error), opts ...Option) (interface{}, error) {
+	return DoResult(ctx, f, opts...)
+}
+
+// Return the delay after d.
+func (o *options) next(d time.Duration) time.Duration {
+	d = time.Duration(float64(d) * o.multiplier)
+	if d > o.maxDelay {
+		d = o.maxDelay
+	}
+	return d
+}
+
+// Return d randomised by the jitter factor.
+func (o *options) jittered(d time.Duration) time.Duration {
+	if o.jitter <= 0 {
+		return d
+	}
+	d += time.Duration((rand.Float64()*2 - 1) * o.jitter * float64(d))
+	if d < 0 {
+		d = 0
+	}
+	return d
+}
+
+// Wait for d, return ctx.Err() if ctx is cancelled before.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,172 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+var errFail = errors.New("fail")
+
+func TestDoSuccess(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return errFail
+		}
+		return nil
+	}, InitialDelay(time.Millisecond))
+
+	if err != nil || calls != 3 {
+		t.Fatal()
+	}
+}
+
+func TestDoMaxAttempts(t *testing.T) {
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		return errFail
+	}, MaxAttempts(5), InitialDelay(time.Millisecond))
+
+	if err != errFail || calls != 5 {
+		t.Fatal()
+	}
+}
+
+func TestDoOnRetry(t *testing.T) {
+	attempts := []int{}
+	Do(context.Background(), func() error {
+		return errFail
+	}, MaxAttempts(3), InitialDelay(0), OnRetry(func(attempt int, err error) {
+		if err != errFail {
+			t.Fatal()
+		}
+		attempts = append(attempts, attempt)
+	}))
+
+	if len(attempts) != 2 || attempts[0] != 1 || attempts[1] != 2 {
+		t.Fatal()
+	}
+}
+
+func TestDoRetryIf(t *testing.T) {
+	fatal := errors.New("fatal")
+	calls := 0
+	err := Do(context.Background(), func() error {
+		calls++
+		if calls == 2 {
+			return fatal
+		}
+		return errFail
+	}, MaxAttempts(5), InitialDelay(0), RetryIf(func(err error) bool {
+		return err != fatal
+	}))
+
+	if err != fatal || calls != 2 {
+		t.Fatal()
+	}
+}
+
+func TestDoContextCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, func() error {
+		calls++
+		cancel()
+		return errFail
+	}, MaxAttempts(5), InitialDelay(time.Hour))
+
+	if err != context.Canceled || calls != 1 {
+		t.Fatal()
+	}
+
+	calls = 0
+	err = Do(ctx, func() error {
+		calls++
+		return nil
+	})
+	if err != context.Canceled || calls != 0 {
+		t.Fatal()
+	}
+}
+
+type response struct {
+	status int
+	body   string
+}
+
+func TestDoResult(t *testing.T) {
+	calls := 0
+	r, err := DoResult(context.Background(), func() (response, error) {
+		calls++
+		if calls < 2 {
+			return response{status: 503}, errFail
+		}
+		return response{200, "ok"}, nil
+	}, InitialDelay(0))
+
+	if err != nil || calls != 2 || r != (response{200, "ok"}) {
+		t.Fatal()
+	}
+
+	r, err = DoResult(context.Background(), func() (response, error) {
+		return response{status: 503}, errFail
+	}, MaxAttempts(2), InitialDelay(0))
+	if err != errFail || r.status != 503 {
+		t.Fatal()
+	}
+}
+
+func TestDoValue(t *testing.T) {
+	calls := 0
+	v, err := DoValue(context.Background(), func() (interface{}, error) {
+		calls++
+		if calls < 3 {
+			return nil, errFail
+		}
+		return response{200, "ok"}, nil
+	}, InitialDelay(0))
+
+	if err != nil || v.(response).body != "ok" {
+		t.Fatal()
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	o := newOptions([]Option{
+		InitialDelay(time.Second),
+		MaxDelay(5 * time.Second),
+		Multiplier(2),
+	})
+
+	d := o.initialDelay
+	expected := []time.Duration{2, 4, 5, 5}
+	for _, e := range expected {
+		d = o.next(d)
+		if d != e*time.Second {
+			t.Fatal()
+		}
+	}
+}
+
+func TestJitter(t *testing.T) {
+	o := newOptions([]Option{Jitter(0.5)})
+	for i := 0; i < 100; i++ {
+		d := o.jittered(time.Second)
+		if d < 500*time.Millisecond || d > 1500*time.Millisecond {
+			t.Fatal()
+		}
+	}
+
+	o = newOptions(nil)
+	if o.jittered(time.Second) != time.Second {
+		t.Fatal()
+	}
+}
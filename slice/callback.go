@@ -0,0 +1,153 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"reflect"
+	"strings"
+)
+
+var (
+	boolType  = reflect.TypeOf(false)
+	intType   = reflect.TypeOf(0)
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// The expected signature of a callback.
+type signature struct {
+	// Parameter types, the argument types must be assignable to them.
+	// A nil type accepts any type, shown as A.
+	in []reflect.Type
+
+	// Result types, a nil type accepts any type, shown as R.
+	out []reflect.Type
+
+	// The results are not used, any results are accepted.
+	anyOut bool
+
+	// Appended to the panic message if the callback takes one parameter,
+	// e.g. a hint about the non-indexed function.
+	hint string
+}
+
+// Reflect the callback f of the exported function name, and check it
+// against sig. role names the callback in the panic message, e.g. "predicate".
+// NOTE: Panic if f is not a func or func pointer, or does not match sig.
+// Example: utils/slice.Filter: predicate must be func(int) bool, got func(int, int) bool.
+func checkFunc(name, role string, f interface{}, sig signature) reflect.Value {
+	v := reflectFunc(f)
+	t := v.Type()
+	if !sig.match(t) {
+		hint := ""
+		if t.NumIn() == 1 {
+			hint = sig.hint
+		}
+		panicCallback(name, role, sig.String(), t, hint)
+	}
+	return v
+}
+
+// Check f is func(elem) bool.
+func checkPredicate(name string, f interface{}, elem reflect.Type) reflect.Value {
+	return checkFunc(name, "predicate", f, signature{
+		in:  []reflect.Type{elem},
+		out: []reflect.Type{boolType},
+	})
+}
+
+// Check f is func(elem) R.
+func checkMapper(name string, f interface{}, elem reflect.Type) reflect.Value {
+	return checkFunc(name, "mapper", f, signature{
+		in:  []reflect.Type{elem},
+		out: []reflect.Type{nil},
+	})
+}
+
+// Check f is func(elem), with any results.
+func checkCallback(name string, f interface{}, elem reflect.Type) reflect.Value {
+	return checkFunc(name, "callback", f, signature{
+		in:     []reflect.Type{elem},
+		anyOut: true,
+	})
+}
+
+// Check f is func(A, elem) A, or func(elem, A) A if right is true.
+func checkAccumulator(name string, f interface{}, elem reflect.Type, right bool) reflect.Value {
+	in, acc, want := []reflect.Type{nil, elem}, 0, "func(A, "+elem.String()+") A"
+	if right {
+		in, acc, want = []reflect.Type{elem, nil}, 1, "func("+elem.String()+", A) A"
+	}
+
+	v := reflectFunc(f)
+	t := v.Type()
+	sig := signature{in: in, out: []reflect.Type{nil}}
+	if !sig.match(t) || !t.Out(0).AssignableTo(t.In(acc)) {
+		panicCallback(name, "accumulator", want, t, "")
+	}
+	return v
+}
+
+// NOTE: Always panic.
+func panicCallback(name, role, want string, got reflect.Type, hint string) {
+	msg := "utils/slice." + name + ": " + role + " must be " + want + ", got " + got.String()
+	if hint != "" {
+		msg += ", " + hint
+	}
+	panic(msg + ".")
+}
+
+// Check func type t against the signature.
+func (sig signature) match(t reflect.Type) bool {
+	if t.IsVariadic() || t.NumIn() != len(sig.in) {
+		return false
+	}
+	for i, in := range sig.in {
+		if in != nil && !in.AssignableTo(t.In(i)) {
+			return false
+		}
+	}
+
+	if sig.anyOut {
+		return true
+	}
+	if t.NumOut() != len(sig.out) {
+		return false
+	}
+	for i, out := range sig.out {
+		if out != nil && !t.Out(i).AssignableTo(out) {
+			return false
+		}
+	}
+	return true
+}
+
+// Format the signature as a func type, e.g. "func(int) bool".
+func (sig signature) String() string {
+	in := make([]string, len(sig.in))
+	for i, t := range sig.in {
+		in[i] = typeName(t, "A")
+	}
+	s := "func(" + strings.Join(in, ", ") + ")"
+
+	if sig.anyOut || len(sig.out) == 0 {
+		return s
+	}
+	out := make([]string, len(sig.out))
+	for i, t := range sig.out {
+		out[i] = typeName(t, "R")
+	}
+	if len(out) == 1 {
+		return s + " " + out[0]
+	}
+	return s + " (" + strings.Join(out, ", ") + ")"
+}
+
+// Return the name of t, or placeholder if t is nil.
+func typeName(t reflect.Type, placeholder string) string {
+	if t == nil {
+		return placeholder
+	}
+	return t.String()
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"testing"
+)
+
+func expectPanic(t *testing.T, msg string, f func()) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if r != msg {
+			t.Fatal(r)
+		}
+	}()
+	f()
+}
+
+func TestCallbackPredicate(t *testing.T) {
+	expectPanic(t, "utils/slice.Filter: predicate must be func(int) bool, got func(int, int) bool.", func() {
+		Filter([]int{1}, func(a, b int) bool { return true })
+	})
+	expectPanic(t, "utils/slice.Exist: predicate must be func(int) bool, got func(int) int.", func() {
+		Exist([]int{1}, func(a int) int { return a })
+	})
+	expectPanic(t, "utils/slice.None: predicate must be func(string) bool, got func(int) bool.", func() {
+		None([]string{"a"}, func(a int) bool { return true })
+	})
+	expectPanic(t, "utils/slice.TakeWhile: predicate must be func(int) bool, got func(...int) bool.", func() {
+		TakeWhile([]int{1}, func(a ...int) bool { return true })
+	})
+}
+
+func TestCallbackMapper(t *testing.T) {
+	expectPanic(t, "utils/slice.Map: mapper must be func(int) R, got func(int).", func() {
+		Map([]int{1}, func(a int) {})
+	})
+	expectPanic(t, "utils/slice.Map: mapper must be func(int) R, got func(int) (int, error).", func() {
+		Map([]int{1}, func(a int) (int, error) { return a, nil })
+	})
+	expectPanic(t, "utils/slice.ParallelMap: mapper must be func(string) R, got func(int) int.", func() {
+		ParallelMap([]string{"a"}, 2, func(a int) int { return a })
+	})
+}
+
+func TestCallbackOthers(t *testing.T) {
+	expectPanic(t, "utils/slice.Foreach: callback must be func(int), got func().", func() {
+		Foreach([]int{1}, func() {})
+	})
+	expectPanic(t, "utils/slice.TryForeach: callback must be func(int) error, got func(int) bool.", func() {
+		TryForeach([]int{1}, func(a int) bool { return true })
+	})
+	expectPanic(t, "utils/slice.Scan: accumulator must be func(A, int) A, got func(string, int) int.", func() {
+		Scan([]int{1}, "", func(acc string, a int) int { return a })
+	})
+	expectPanic(t, "utils/slice.BinarySearchLess: less must be func(int, int) bool, got func(int) bool.", func() {
+		BinarySearchLess([]int{1}, 1, func(a int) bool { return true })
+	})
+}
+
+func TestCallbackAssignable(t *testing.T) {
+	// Elements are assignable to interface parameters, results are
+	// not used by Foreach.
+	n := 0
+	Foreach([]int{1, 2}, func(a interface{}) int { n++; return n })
+	if n != 2 {
+		t.Fatal()
+	}
+	if Count([]string{"a", "bb"}, func(s interface{}) bool { return len(s.(string)) > 1 }) != 1 {
+		t.Fatal()
+	}
+}
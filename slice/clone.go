@@ -0,0 +1,142 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"reflect"
+)
+
+// The nesting level below which DeepClone shares values instead of copying.
+const deepCloneMaxDepth = 1000
+
+// Copy the elements into a new backing array, and return a slice of the same
+// type, e.g. before handing the slice to another goroutine.
+// The elements themselves are not copied, see DeepClone.
+// Return nil slice of the same type if i is nil slice.
+// NOTE: Panic if i is not slice or slice pointer.
+func Clone(i interface{}) interface{} {
+	v := reflectSlice(i)
+	if v.IsNil() {
+		return reflect.Zero(v.Type()).Interface()
+	}
+
+	result := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+	reflect.Copy(result, v)
+	return result.Interface()
+}
+
+// Same as Clone, but also copy the values the elements refer to, recursively
+// through pointers, slices, maps, arrays, interfaces and exported struct
+// fields, so the result shares no mutable memory with i.
+// Values referenced more than once, including cycles, are copied once, and
+// the copy keeps the same sharing.
+// Unexported struct fields are copied shallowly, and so are map keys, funcs,
+// chans and values nested deeper than 1000 levels.
+// NOTE: Panic if i is not slice or slice pointer.
+func DeepClone(i interface{}) interface{} {
+	v := reflectSlice(i)
+	result := reflect.New(v.Type()).Elem()
+	c := &cloner{visited: make(map[cloneKey]reflect.Value)}
+	c.copy(result, v, 0)
+	return result.Interface()
+}
+
+// Identify a pointer, slice or map already copied by a cloner.
+type cloneKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+type cloner struct {
+	visited map[cloneKey]reflect.Value
+}
+
+// Set dst to a deep copy of src, dst must be settable.
+func (c *cloner) copy(dst, src reflect.Value, depth int) {
+	if depth > deepCloneMaxDepth {
+		dst.Set(src)
+		return
+	}
+
+	switch src.Kind() {
+	case reflect.Ptr:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		key := cloneKey{src.Pointer(), src.Type(), 0}
+		if p, ok := c.visited[key]; ok {
+			dst.Set(p)
+			return
+		}
+		p := reflect.New(src.Type().Elem())
+		c.visited[key] = p
+		c.copy(p.Elem(), src.Elem(), depth+1)
+		dst.Set(p)
+
+	case reflect.Slice:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		key := cloneKey{src.Pointer(), src.Type(), src.Len()}
+		if s, ok := c.visited[key]; ok {
+			dst.Set(s)
+			return
+		}
+		s := reflect.MakeSlice(src.Type(), src.Len(), src.Len())
+		c.visited[key] = s
+		for i := 0; i < src.Len(); i++ {
+			c.copy(s.Index(i), src.Index(i), depth+1)
+		}
+		dst.Set(s)
+
+	case reflect.Map:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		key := cloneKey{src.Pointer(), src.Type(), 0}
+		if m, ok := c.visited[key]; ok {
+			dst.Set(m)
+			return
+		}
+		m := reflect.MakeMapWithSize(src.Type(), src.Len())
+		c.visited[key] = m
+		iter := src.MapRange()
+		for iter.Next() {
+			e := reflect.New(src.Type().Elem()).Elem()
+			c.copy(e, iter.Value(), depth+1)
+			m.SetMapIndex(iter.Key(), e)
+		}
+		dst.Set(m)
+
+	case reflect.Array:
+		for i := 0; i < src.Len(); i++ {
+			c.copy(dst.Index(i), src.Index(i), depth+1)
+		}
+
+	case reflect.Struct:
+		dst.Set(src)
+		for i := 0; i < src.NumField(); i++ {
+			if f := dst.Field(i); f.CanSet() {
+				c.copy(f, src.Field(i), depth+1)
+			}
+		}
+
+	case reflect.Interface:
+		if src.IsNil() {
+			dst.Set(src)
+			return
+		}
+		e := reflect.New(src.Elem().Type()).Elem()
+		c.copy(e, src.Elem(), depth+1)
+		dst.Set(e)
+
+	default:
+		dst.Set(src)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	a := []int{1, 2, 3}
+	b := Clone(a).([]int)
+	b[0] = 9
+	if !reflect.DeepEqual(a, []int{1, 2, 3}) || !reflect.DeepEqual(b, []int{9, 2, 3}) {
+		t.Fatal(a, b)
+	}
+
+	p := &a
+	if c := Clone(p).([]int); !reflect.DeepEqual(c, a) {
+		t.Fatal(c)
+	}
+
+	var nilSlice []string
+	if c := Clone(nilSlice).([]string); c != nil {
+		t.Fatal(c)
+	}
+
+	one := 1
+	ptrs := []*int{&one}
+	if c := Clone(ptrs).([]*int); c[0] != &one {
+		t.Fatal()
+	}
+}
+
+type cloneNode struct {
+	Name     string
+	Tags     []string
+	Attrs    map[string][]int
+	Next     *cloneNode
+	Any      interface{}
+	Grid     [2][]int
+	internal []int
+}
+
+func TestDeepClone(t *testing.T) {
+	one := 1
+	ints := DeepClone([]*int{&one}).([]*int)
+	*ints[0] = 2
+	if one != 1 {
+		t.Fatal()
+	}
+
+	nested := [][]int{{1, 2}, {3}}
+	c := DeepClone(nested).([][]int)
+	c[0][0] = 9
+	if nested[0][0] != 1 {
+		t.Fatal(nested)
+	}
+
+	maps := []map[string][]int{{"a": {1}}}
+	cm := DeepClone(maps).([]map[string][]int)
+	cm[0]["a"][0] = 9
+	cm[0]["b"] = nil
+	if !reflect.DeepEqual(maps, []map[string][]int{{"a": {1}}}) {
+		t.Fatal(maps)
+	}
+
+	internal := []int{1}
+	nodes := []cloneNode{{
+		Name:     "a",
+		Tags:     []string{"x"},
+		Attrs:    map[string][]int{"k": {1}},
+		Next:     &cloneNode{Name: "b", Tags: []string{"y"}},
+		Any:      []int{1},
+		Grid:     [2][]int{{1}, {2}},
+		internal: internal,
+	}}
+	cn := DeepClone(nodes).([]cloneNode)
+	if !reflect.DeepEqual(cn, nodes) {
+		t.Fatal(cn)
+	}
+	cn[0].Tags[0] = "z"
+	cn[0].Attrs["k"][0] = 9
+	cn[0].Next.Tags[0] = "z"
+	cn[0].Any.([]int)[0] = 9
+	cn[0].Grid[1][0] = 9
+	if nodes[0].Tags[0] != "x" || nodes[0].Attrs["k"][0] != 1 || nodes[0].Next.Tags[0] != "y" ||
+		nodes[0].Any.([]int)[0] != 1 || nodes[0].Grid[1][0] != 2 {
+		t.Fatal(nodes)
+	}
+	if &cn[0].internal[0] != &internal[0] {
+		t.Fatal()
+	}
+
+	var nilSlice []int
+	if DeepClone(nilSlice).([]int) != nil {
+		t.Fatal()
+	}
+}
+
+func TestDeepCloneShared(t *testing.T) {
+	n := &cloneNode{Name: "a"}
+	n.Next = n
+	c := DeepClone([]*cloneNode{n, n}).([]*cloneNode)
+	if c[0] == n || c[0] != c[1] || c[0].Next != c[0] {
+		t.Fatal()
+	}
+
+	s := []interface{}{nil}
+	s[0] = s
+	cs := DeepClone(s).([]interface{})
+	if &cs[0].([]interface{})[0] != &cs[0] {
+		t.Fatal()
+	}
+}
+
+func TestDeepCloneMaxDepth(t *testing.T) {
+	head := &cloneNode{}
+	tail := head
+	for i := 0; i < deepCloneMaxDepth; i++ {
+		tail.Next = &cloneNode{}
+		tail = tail.Next
+	}
+
+	c := DeepClone([]*cloneNode{head}).([]*cloneNode)[0]
+	if c == head {
+		t.Fatal()
+	}
+	for c.Next != nil && c.Next.Next != nil {
+		c = c.Next
+	}
+	if c.Next != tail {
+		t.Fatal()
+	}
+}
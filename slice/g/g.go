@@ -0,0 +1,333 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+// Useful functions for handle slice, the type safe counterpart of package
+// utils/slice, running without reflection. The generic slice functions
+// live here, package slice only has the reflection ones.
+// Foreach, Map, MapIdx, Filter, Find, Index and Exist take the same
+// arguments as their slice counterparts, so migration is mechanical.
+// FoldLeft and FoldRight are the counterparts of slice.FoldLeftR and
+// slice.FoldRightR, and SampleWithRand of slice.Sample. The other functions
+// have no reflection counterpart.
+package g
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+// Traverse the slice, call function f by element in order.
+func Foreach[T any](s []T, f func(T)) {
+	for _, v := range s {
+		f(v)
+	}
+}
+
+// Map the slice to another slice, convert element by function f in order.
+func Map[T, R any](s []T, f func(T) R) []R {
+	result := make([]R, len(s))
+	for i, v := range s {
+		result[i] = f(v)
+	}
+	return result
+}
+
+// Filter element satisfy function f, then return a new slice.
+// If no element satisfied, return an empty slice.
+func Filter[T any](s []T, f func(T) bool) []T {
+	result := make([]T, 0)
+	for _, v := range s {
+		if f(v) {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Same as FoldLeft, aggregate the elements into a single value from left to
+// right, by calling f with the accumulated value (initial at first) and
+// every element.
+func Reduce[T, A any](s []T, initial A, f func(A, T) A) A {
+	return FoldLeft(s, initial, f)
+}
+
+// Find first element satisfy function f.
+// Return false and the zero value, if no element satisfy.
+func Find[T any](s []T, f func(T) bool) (bool, T) {
+	for _, v := range s {
+		if f(v) {
+			return true, v
+		}
+	}
+	var zero T
+	return false, zero
+}
+
+// Get first element index satisfy function f
+// Return -1, if no element satisfy.
+func Index[T any](s []T, f func(T) bool) int {
+	for i, v := range s {
+		if f(v) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Check if the slice has element satisfy function f.
+// Return true if slice has at least such one element, Otherwise false.
+func Exist[T any](s []T, f func(T) bool) bool {
+	return Index(s, f) != -1
+}
+
+// Group the elements by the key returned by f, in the order of s.
+func GroupBy[T any, K comparable](s []T, f func(T) K) map[K][]T {
+	result := make(map[K][]T)
+	for _, v := range s {
+		k := f(v)
+		result[k] = append(result[k], v)
+	}
+	return result
+}
+
+// Return a new slice without duplicate elements, the first one is kept.
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]bool, len(s))
+	result := make([]T, 0, len(s))
+	for _, v := range s {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// Check if the slice contains v.
+func Contains[T comparable](s []T, v T) bool {
+	for _, e := range s {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Split the slice into chunks of size elements, the last chunk may be
+// shorter. The chunks share the backing array of s, and their capacity is
+// limited to their length, so appending to a chunk does not overwrite the next.
+// NOTE: Panic if size <= 0.
+func Chunk[T any](s []T, size int) [][]T {
+	if size <= 0 {
+		panic(fmt.Sprintf("utils/slice/g: chunk size %d is not positive.", size))
+	}
+
+	result := make([][]T, 0, (len(s)+size-1)/size)
+	for from := 0; from < len(s); from += size {
+		to := from + size
+		if to > len(s) {
+			to = len(s)
+		}
+		result = append(result, s[from:to:to])
+	}
+	return result
+}
+
+// Split the slice in a single pass, into the elements satisfy f and the rest.
+// The order of s is kept in both.
+func Partition[T any](s []T, f func(T) bool) (matched, unmatched []T) {
+	matched, unmatched = make([]T, 0), make([]T, 0)
+	for _, v := range s {
+		if f(v) {
+			matched = append(matched, v)
+		} else {
+			unmatched = append(unmatched, v)
+		}
+	}
+	return matched, unmatched
+}
+
+// Return n distinct elements chosen uniformly at random without replacement.
+// If n > len(s), return all elements in random order. s is not modified.
+func Sample[T any](s []T, n int) []T {
+	return sample(s, n, rand.Shuffle)
+}
+
+// Same as Sample, but using the random source r, for reproducible results.
+func SampleWithRand[T any](s []T, n int, r *rand.Rand) []T {
+	return sample(s, n, r.Shuffle)
+}
+
+func sample[T any](s []T, n int, shuffle func(n int, swap func(i, j int))) []T {
+	if n > len(s) {
+		n = len(s)
+	} else if n < 0 {
+		n = 0
+	}
+
+	result := make([]T, len(s))
+	copy(result, s)
+	shuffle(len(result), func(i, j int) {
+		result[i], result[j] = result[j], result[i]
+	})
+	return result[:n:n]
+}
+
+// Map the slice to another slice, convert element by function f with the
+// index and the element, in order.
+// Example: g.MapIdx([]string{"a", "b"}, prefix) returns []string{"0:a", "1:b"}
+func MapIdx[T, U any](s []T, f func(int, T) U) []U {
+	result := make([]U, len(s))
+	for i, v := range s {
+		result[i] = f(i, v)
+	}
+	return result
+}
+
+// Fold the slice into a single value from left to right.
+// Example: g.FoldLeft([]string{"a", "b"}, "", concat) returns "ab"
+func FoldLeft[T, A any](s []T, initial A, f func(A, T) A) A {
+	acc := initial
+	for _, v := range s {
+		acc = f(acc, v)
+	}
+	return acc
+}
+
+// Fold the slice into a single value from right to left.
+// Example: g.FoldRight([]string{"a", "b"}, "", concat) returns "ba"
+func FoldRight[T, A any](s []T, initial A, f func(T, A) A) A {
+	acc := initial
+	for i := len(s) - 1; i >= 0; i-- {
+		acc = f(s[i], acc)
+	}
+	return acc
+}
+
+// Return a new slice with the elements rotated left by n positions,
+// s is not modified. n is taken modulo len(s), a negative n rotates right.
+// Example: g.RotateLeft([]int{1, 2, 3, 4}, 1) returns []int{2, 3, 4, 1}
+func RotateLeft[T any](s []T, n int) []T {
+	result := make([]T, len(s))
+	copy(result, s)
+	RotateLeftInPlace(result, n)
+	return result
+}
+
+// Same as RotateLeft, but rotate right.
+// Example: g.RotateRight([]int{1, 2, 3, 4}, 1) returns []int{4, 1, 2, 3}
+func RotateRight[T any](s []T, n int) []T {
+	return RotateLeft(s, -n)
+}
+
+// Same as RotateLeft, but rotate s in place, in O(n) time by three reversals.
+func RotateLeftInPlace[T any](s []T, n int) {
+	if len(s) == 0 {
+		return
+	}
+	n %= len(s)
+	if n < 0 {
+		n += len(s)
+	}
+	if n == 0 {
+		return
+	}
+
+	reverse(s[:n])
+	reverse(s[n:])
+	reverse(s)
+}
+
+// Same as RotateRight, but rotate s in place.
+func RotateRightInPlace[T any](s []T, n int) {
+	RotateLeftInPlace(s, -n)
+}
+
+func reverse[T any](s []T) {
+	for i, j := 0, len(s)-1; i < j; i, j = i+1, j-1 {
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Return a new slice with the element at from moved to to, the elements
+// between are shifted by one. s is not modified.
+// Example: g.Move([]int{1, 2, 3, 4}, 0, 2) returns []int{2, 3, 1, 4}
+// NOTE: Panic if from or to is out of range.
+func Move[T any](s []T, from, to int) []T {
+	checkIndex(from, len(s))
+	checkIndex(to, len(s))
+
+	result := make([]T, len(s))
+	copy(result, s)
+	v := result[from]
+	if from < to {
+		copy(result[from:to], result[from+1:to+1])
+	} else {
+		copy(result[to+1:from+1], result[to:from])
+	}
+	result[to] = v
+	return result
+}
+
+// Return a new slice with the elements at i and j swapped, s is not modified.
+// NOTE: Panic if i or j is out of range.
+func Swap[T any](s []T, i, j int) []T {
+	checkIndex(i, len(s))
+	checkIndex(j, len(s))
+
+	result := make([]T, len(s))
+	copy(result, s)
+	result[i], result[j] = result[j], result[i]
+	return result
+}
+
+// NOTE: Panic if index is out of range [0, n).
+func checkIndex(index, n int) {
+	if index < 0 || index >= n {
+		panic(fmt.Sprintf("utils/slice/g: index %d out of range [0, %d).", index, n))
+	}
+}
+
+// Return a new slice with s[index] replaced by v, s is not modified.
+// NOTE: Panic if index is out of range.
+func Replace[T any](s []T, index int, v T) []T {
+	checkIndex(index, len(s))
+
+	result := make([]T, len(s))
+	copy(result, s)
+	result[index] = v
+	return result
+}
+
+// Return a new slice with every old replaced by new, s is not modified.
+func ReplaceAll[T comparable](s []T, old, new T) []T {
+	result := make([]T, len(s))
+	for i, v := range s {
+		if v == old {
+			v = new
+		}
+		result[i] = v
+	}
+	return result
+}
+
+// Return a new slice with the first old replaced by new, s is not modified.
+func ReplaceFirst[T comparable](s []T, old, new T) []T {
+	for i, v := range s {
+		if v == old {
+			return Replace(s, i, new)
+		}
+	}
+	return append([]T{}, s...)
+}
+
+// Return a new slice with the last old replaced by new, s is not modified.
+func ReplaceLast[T comparable](s []T, old, new T) []T {
+	for i := len(s) - 1; i >= 0; i-- {
+		if s[i] == old {
+			return Replace(s, i, new)
+		}
+	}
+	return append([]T{}, s...)
+}
@@ -0,0 +1,377 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package g
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/uestcer/utils/slice"
+)
+
+// The functions shared by package g and package slice, on int slices.
+type impl struct {
+	name    string
+	foreach func(s []int, f func(int))
+	mapf    func(s []int, f func(int) int) []int
+	filter  func(s []int, f func(int) bool) []int
+	find    func(s []int, f func(int) bool) (bool, int)
+	index   func(s []int, f func(int) bool) int
+	exist   func(s []int, f func(int) bool) bool
+}
+
+func toInts(r []interface{}) []int {
+	ints := make([]int, len(r))
+	for i, v := range r {
+		ints[i] = v.(int)
+	}
+	return ints
+}
+
+var impls = []impl{
+	{
+		name:    "g",
+		foreach: Foreach[int],
+		mapf:    Map[int, int],
+		filter:  Filter[int],
+		find:    Find[int],
+		index:   Index[int],
+		exist:   Exist[int],
+	},
+	{
+		name:    "slice",
+		foreach: func(s []int, f func(int)) { slice.Foreach(s, f) },
+		mapf:    func(s []int, f func(int) int) []int { return toInts(slice.Map(s, f)) },
+		filter:  func(s []int, f func(int) bool) []int { return toInts(slice.Filter(s, f)) },
+		find: func(s []int, f func(int) bool) (bool, int) {
+			ok, v := slice.Find(s, f)
+			n, _ := v.(int)
+			return ok, n
+		},
+		index: func(s []int, f func(int) bool) int { return slice.Index(s, f) },
+		exist: func(s []int, f func(int) bool) bool { return slice.Exist(s, f) },
+	},
+}
+
+func even(i int) bool { return i%2 == 0 }
+
+func TestShared(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	for _, m := range impls {
+		sum := 0
+		m.foreach(s, func(i int) { sum += i })
+		if sum != 10 {
+			t.Fatal(m.name)
+		}
+
+		if !reflect.DeepEqual(m.mapf(s, func(i int) int { return i * 10 }), []int{10, 20, 30, 40}) ||
+			!reflect.DeepEqual(m.mapf(nil, func(i int) int { return i }), []int{}) {
+			t.Fatal(m.name)
+		}
+
+		if !reflect.DeepEqual(m.filter(s, even), []int{2, 4}) ||
+			!reflect.DeepEqual(m.filter(s, func(i int) bool { return false }), []int{}) {
+			t.Fatal(m.name)
+		}
+
+		if ok, v := m.find(s, even); !ok || v != 2 {
+			t.Fatal(m.name)
+		}
+		if ok, v := m.find(s, func(i int) bool { return i > 4 }); ok || v != 0 {
+			t.Fatal(m.name)
+		}
+
+		if m.index(s, even) != 1 || m.index(s, func(i int) bool { return i > 4 }) != -1 {
+			t.Fatal(m.name)
+		}
+		if !m.exist(s, even) || m.exist(nil, even) {
+			t.Fatal(m.name)
+		}
+	}
+}
+
+func TestReduce(t *testing.T) {
+	r := Reduce([]string{"a", "b"}, ">", func(acc string, s string) string { return acc + s })
+	if r != ">ab" {
+		t.Fatal(r)
+	}
+}
+
+func TestGroupBy(t *testing.T) {
+	r := GroupBy([]string{"a", "bb", "c", "dd"}, func(s string) int { return len(s) })
+	if !reflect.DeepEqual(r, map[int][]string{1: {"a", "c"}, 2: {"bb", "dd"}}) {
+		t.Fatal(r)
+	}
+}
+
+func TestUniqueContains(t *testing.T) {
+	if !reflect.DeepEqual(Unique([]int{3, 1, 3, 2, 1}), []int{3, 1, 2}) ||
+		!reflect.DeepEqual(Unique([]int{}), []int{}) {
+		t.Fatal()
+	}
+	if !Contains([]string{"a", "b"}, "b") || Contains([]string{"a"}, "c") || Contains(nil, "") {
+		t.Fatal()
+	}
+}
+
+func TestChunk(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	r := Chunk(s, 2)
+	if !reflect.DeepEqual(r, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Fatal(r)
+	}
+	r[0] = append(r[0], 100)
+	if s[2] != 3 {
+		t.Fatal(s)
+	}
+	if len(Chunk([]int{}, 3)) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestChunkPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice/g: chunk size 0 is not positive." {
+			t.Fatal(r)
+		}
+	}()
+	Chunk([]int{1}, 0)
+}
+
+func TestPartition(t *testing.T) {
+	matched, unmatched := Partition([]string{"a", "bb", "c"}, func(s string) bool {
+		return strings.HasPrefix(s, "b")
+	})
+	if !reflect.DeepEqual(matched, []string{"bb"}) || !reflect.DeepEqual(unmatched, []string{"a", "c"}) {
+		t.Fatal()
+	}
+}
+
+func TestSample(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	r := Sample(s, 3)
+	if len(r) != 3 || len(Unique(r)) != 3 {
+		t.Fatal(r)
+	}
+	for _, v := range r {
+		if !Contains(s, v) {
+			t.Fatal(r)
+		}
+	}
+
+	all := Sample(s, 10)
+	sort.Ints(all)
+	if !reflect.DeepEqual(all, s) {
+		t.Fatal(all)
+	}
+	if len(Sample(s, -1)) != 0 || len(Sample([]int{}, 2)) != 0 {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4, 5}) {
+		t.Fatal(s)
+	}
+}
+
+func TestSampleWithRand(t *testing.T) {
+	s := []string{"a", "b", "c", "d", "e", "f"}
+	r1 := SampleWithRand(s, 3, rand.New(rand.NewSource(7)))
+	r2 := SampleWithRand(s, 3, rand.New(rand.NewSource(7)))
+	if !reflect.DeepEqual(r1, r2) {
+		t.Fatal(r1, r2)
+	}
+}
+
+var benchInts = func() []int {
+	s := make([]int, 1000)
+	for i := range s {
+		s[i] = i
+	}
+	return s
+}()
+
+func BenchmarkMapG(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Map(benchInts, func(i int) int { return i * 2 })
+	}
+}
+
+func BenchmarkMapReflect(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		slice.Map(benchInts, func(i int) int { return i * 2 })
+	}
+}
+
+func BenchmarkFilterG(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		Filter(benchInts, even)
+	}
+}
+
+func BenchmarkFilterReflect(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		slice.Filter(benchInts, even)
+	}
+}
+
+func TestMapIdx(t *testing.T) {
+	r := MapIdx([]string{"a", "b"}, func(i int, s string) string {
+		return fmt.Sprint(i, ":", s)
+	})
+	if !reflect.DeepEqual(r, []string{"0:a", "1:b"}) {
+		t.Fatal()
+	}
+
+	m := map[int]string{}
+	MapIdx([]string{"x", "y"}, func(i int, s string) bool {
+		m[i] = s
+		return true
+	})
+	if !reflect.DeepEqual(m, map[int]string{0: "x", 1: "y"}) {
+		t.Fatal()
+	}
+
+	if len(MapIdx(nil, func(i int, s string) int { return i })) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestFoldLeftRight(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	l := FoldLeft(s, ">", func(acc string, v string) string { return acc + v })
+	r := FoldRight(s, ">", func(v string, acc string) string { return acc + v })
+	if l != ">abc" || r != ">cba" {
+		t.Fatal(l, r)
+	}
+
+	n := FoldLeft([]int{1, 2, 3}, 0, func(acc int, v int) int { return acc*10 + v })
+	if n != 123 {
+		t.Fatal(n)
+	}
+	if FoldRight([]int{}, 7, func(v int, acc int) int { return 0 }) != 7 {
+		t.Fatal()
+	}
+}
+
+func TestRotateLeftRight(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(RotateLeft(s, 1), []int{2, 3, 4, 1}) ||
+		!reflect.DeepEqual(RotateLeft(s, 6), []int{3, 4, 1, 2}) ||
+		!reflect.DeepEqual(RotateLeft(s, -1), []int{4, 1, 2, 3}) ||
+		!reflect.DeepEqual(RotateRight(s, 1), []int{4, 1, 2, 3}) ||
+		!reflect.DeepEqual(RotateRight(s, -5), []int{2, 3, 4, 1}) ||
+		!reflect.DeepEqual(RotateRight(s, 4), []int{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4}) {
+		t.Fatal(s)
+	}
+	if len(RotateLeft([]int{}, 3)) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestRotateInPlace(t *testing.T) {
+	s := []string{"a", "b", "c", "d", "e"}
+	RotateLeftInPlace(s, 2)
+	if !reflect.DeepEqual(s, []string{"c", "d", "e", "a", "b"}) {
+		t.Fatal(s)
+	}
+	RotateRightInPlace(s, 12)
+	if !reflect.DeepEqual(s, []string{"a", "b", "c", "d", "e"}) {
+		t.Fatal(s)
+	}
+	RotateRightInPlace([]string(nil), 1)
+}
+
+func TestMove(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(Move(s, 0, 2), []int{2, 3, 1, 4}) ||
+		!reflect.DeepEqual(Move(s, 3, 1), []int{1, 4, 2, 3}) ||
+		!reflect.DeepEqual(Move(s, 2, 2), []int{1, 2, 3, 4}) ||
+		!reflect.DeepEqual(Move(s, 0, 3), []int{2, 3, 4, 1}) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4}) {
+		t.Fatal(s)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(Swap(s, 0, 2), []string{"c", "b", "a"}) ||
+		!reflect.DeepEqual(Swap(s, 1, 1), []string{"a", "b", "c"}) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []string{"a", "b", "c"}) {
+		t.Fatal(s)
+	}
+}
+
+func TestMoveSwapPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice/g: index 3 out of range [0, 3)." {
+			t.Fatal(r)
+		}
+	}()
+	Move([]int{1, 2, 3}, 0, 3)
+}
+
+func TestMoveSwapProperty(t *testing.T) {
+	// Both keep the length and the elements, and Move then moving back
+	// restores the input.
+	f := func(s []int, i, j uint) bool {
+		if len(s) == 0 {
+			return true
+		}
+		from, to := int(i%uint(len(s))), int(j%uint(len(s)))
+		moved, swapped := Move(s, from, to), Swap(s, from, to)
+
+		return len(moved) == len(s) && len(swapped) == len(s) &&
+			moved[to] == s[from] && swapped[to] == s[from] && swapped[from] == s[to] &&
+			reflect.DeepEqual(Move(moved, to, from), s)
+	}
+	if err := quick.Check(f, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReplace(t *testing.T) {
+	s := []int{1, 2, 1, 3, 1}
+	if !reflect.DeepEqual(Replace(s, 1, 9), []int{1, 9, 1, 3, 1}) ||
+		!reflect.DeepEqual(ReplaceAll(s, 1, 0), []int{0, 2, 0, 3, 0}) ||
+		!reflect.DeepEqual(ReplaceFirst(s, 1, 0), []int{0, 2, 1, 3, 1}) ||
+		!reflect.DeepEqual(ReplaceLast(s, 1, 0), []int{1, 2, 1, 3, 0}) ||
+		!reflect.DeepEqual(ReplaceFirst(s, 7, 0), s) ||
+		!reflect.DeepEqual(ReplaceLast(s, 7, 0), s) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 1, 3, 1}) {
+		t.Fatal(s)
+	}
+
+	// The result does not alias the input, even without a match.
+	r := ReplaceFirst(s, 7, 0)
+	r[0] = 100
+	if s[0] != 1 {
+		t.Fatal(s)
+	}
+}
+
+func TestReplacePanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice/g: index -1 out of range [0, 2)." {
+			t.Fatal(r)
+		}
+	}()
+	Replace([]int{1, 2}, -1, 0)
+}
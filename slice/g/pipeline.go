@@ -0,0 +1,125 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package g
+
+import (
+	"sort"
+)
+
+// A type safe chain of operations on a slice, created by Of.
+// The operations are lazy, the elements flow through them one by one when
+// a terminal method (Collect, First, ForEach, Count) is called, so First
+// stops at the first element reaching it. Only Sort buffers the elements.
+// Every terminal call evaluates the pipeline again from the source slice.
+// Example: g.Of(users).Filter(active).Take(10).Collect()
+type Pipeline[T any] struct {
+	// Call yield with every element in order, until yield returns false.
+	each func(yield func(T) bool)
+}
+
+// Create a pipeline with the elements of s, s is not copied.
+func Of[T any](s []T) *Pipeline[T] {
+	return &Pipeline[T]{func(yield func(T) bool) {
+		for _, v := range s {
+			if !yield(v) {
+				return
+			}
+		}
+	}}
+}
+
+// Map the elements of p by f. It is a function rather than a method,
+// because methods can not have type parameters.
+func PipeMap[T, U any](p *Pipeline[T], f func(T) U) *Pipeline[U] {
+	return &Pipeline[U]{func(yield func(U) bool) {
+		p.each(func(v T) bool {
+			return yield(f(v))
+		})
+	}}
+}
+
+// Keep the elements satisfy f.
+func (p *Pipeline[T]) Filter(f func(T) bool) *Pipeline[T] {
+	return &Pipeline[T]{func(yield func(T) bool) {
+		p.each(func(v T) bool {
+			return !f(v) || yield(v)
+		})
+	}}
+}
+
+// Sort the elements by less, the order of equal elements is kept.
+func (p *Pipeline[T]) Sort(less func(a, b T) bool) *Pipeline[T] {
+	return &Pipeline[T]{func(yield func(T) bool) {
+		s := p.Collect()
+		sort.SliceStable(s, func(i, j int) bool {
+			return less(s[i], s[j])
+		})
+		Of(s).each(yield)
+	}}
+}
+
+// Keep the first n elements, none if n <= 0.
+func (p *Pipeline[T]) Take(n int) *Pipeline[T] {
+	return &Pipeline[T]{func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		p.each(func(v T) bool {
+			i++
+			return yield(v) && i < n
+		})
+	}}
+}
+
+// Skip the first n elements.
+func (p *Pipeline[T]) Drop(n int) *Pipeline[T] {
+	return &Pipeline[T]{func(yield func(T) bool) {
+		i := 0
+		p.each(func(v T) bool {
+			i++
+			return i <= n || yield(v)
+		})
+	}}
+}
+
+// Return the elements as a new slice, empty if there is no element.
+func (p *Pipeline[T]) Collect() []T {
+	result := make([]T, 0)
+	p.each(func(v T) bool {
+		result = append(result, v)
+		return true
+	})
+	return result
+}
+
+// Return the first element, false if there is no element.
+func (p *Pipeline[T]) First() (T, bool) {
+	var first T
+	found := false
+	p.each(func(v T) bool {
+		first, found = v, true
+		return false
+	})
+	return first, found
+}
+
+// Call f by every element in order.
+func (p *Pipeline[T]) ForEach(f func(T)) {
+	p.each(func(v T) bool {
+		f(v)
+		return true
+	})
+}
+
+// Return the number of elements.
+func (p *Pipeline[T]) Count() int {
+	n := 0
+	p.each(func(T) bool {
+		n++
+		return true
+	})
+	return n
+}
@@ -0,0 +1,75 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package g
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	s := []int{5, 3, 8, 1, 9, 2}
+	r := Of(s).
+		Filter(func(i int) bool { return i > 1 }).
+		Sort(func(a, b int) bool { return a < b }).
+		Drop(1).
+		Take(3).
+		Collect()
+	if !reflect.DeepEqual(r, []int{3, 5, 8}) {
+		t.Fatal(r)
+	}
+	if !reflect.DeepEqual(s, []int{5, 3, 8, 1, 9, 2}) {
+		t.Fatal(s)
+	}
+
+	strs := PipeMap(Of(s).Take(2), strconv.Itoa).Collect()
+	if !reflect.DeepEqual(strs, []string{"5", "3"}) {
+		t.Fatal(strs)
+	}
+
+	if n := Of(s).Filter(func(i int) bool { return i%2 == 0 }).Count(); n != 2 {
+		t.Fatal(n)
+	}
+
+	sum := 0
+	Of(s).Drop(4).ForEach(func(i int) { sum += i })
+	if sum != 11 {
+		t.Fatal(sum)
+	}
+}
+
+func TestPipelineEmpty(t *testing.T) {
+	if r := Of([]int{1, 2}).Take(0).Collect(); !reflect.DeepEqual(r, []int{}) {
+		t.Fatal(r)
+	}
+	if r := Of([]int{1, 2}).Drop(5).Collect(); !reflect.DeepEqual(r, []int{}) {
+		t.Fatal(r)
+	}
+	if v, ok := Of([]string(nil)).First(); ok || v != "" {
+		t.Fatal(v, ok)
+	}
+}
+
+func TestPipelineLazy(t *testing.T) {
+	calls := 0
+	p := PipeMap(Of([]int{1, 2, 3, 4}), func(i int) int {
+		calls++
+		return i * 10
+	})
+	if calls != 0 {
+		t.Fatal(calls)
+	}
+
+	v, ok := p.Filter(func(i int) bool { return i > 10 }).First()
+	if !ok || v != 20 || calls != 2 {
+		t.Fatal(v, ok, calls)
+	}
+
+	calls = 0
+	if r := p.Take(1).Collect(); !reflect.DeepEqual(r, []int{10}) || calls != 1 {
+		t.Fatal(r, calls)
+	}
+}
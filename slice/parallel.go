@@ -0,0 +1,114 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"fmt"
+	"runtime"
+	"sync"
+)
+
+// Same as Map, but f is called concurrently by at most workers goroutines.
+// The results keep the order of the input. workers <= 0 means GOMAXPROCS.
+// If f panics, the remaining elements are skipped, and the first panic is
+// raised again on the calling goroutine, wrapped in a *WorkerPanic.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func ParallelMap(i interface{}, workers int, f interface{}) []interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkMapper("ParallelMap", f, v1.Type().Elem())
+
+	result := make([]interface{}, v1.Len())
+	parallel(v1.Len(), workers, func(i int) {
+		result[i] = call(v2, v1.Index(i))[0].Interface()
+	})
+	return result
+}
+
+// Same as Foreach, but f is called concurrently by at most workers goroutines.
+// workers <= 0 means GOMAXPROCS. Panics are handled the same as in ParallelMap.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func ParallelForeach(i interface{}, workers int, f interface{}) {
+	v1 := reflectSlice(i)
+	v2 := checkCallback("ParallelForeach", f, v1.Type().Elem())
+
+	parallel(v1.Len(), workers, func(i int) {
+		call(v2, v1.Index(i))
+	})
+}
+
+// The value raised again by ParallelMap and ParallelForeach when the
+// function panics in a worker.
+type WorkerPanic struct {
+	// Index of the element being processed.
+	Index int
+
+	// The original panic value.
+	Value interface{}
+
+	// Stack trace of the worker goroutine.
+	Stack string
+}
+
+func (p *WorkerPanic) Error() string {
+	return fmt.Sprintf("utils/slice: panic at element %d: %v\n%s", p.Index, p.Value, p.Stack)
+}
+
+// Call f with every index in [0, n) by at most workers goroutines, and wait
+// for them. Stop on the first panic, and raise it on the calling goroutine.
+func parallel(n, workers int, f func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > n {
+		workers = n
+	}
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		panicked *WorkerPanic
+		stop     = make(chan struct{})
+		indexes  = make(chan int)
+	)
+
+	call := func(i int) {
+		defer func() {
+			if r := recover(); r != nil {
+				buf := make([]byte, 4096)
+				buf = buf[:runtime.Stack(buf, false)]
+				once.Do(func() {
+					panicked = &WorkerPanic{i, r, string(buf)}
+					close(stop)
+				})
+			}
+		}()
+		f(i)
+	}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				call(i)
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-stop:
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if panicked != nil {
+		panic(panicked)
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParallelMap(t *testing.T) {
+	s := []int{5, 1, 4, 2, 3, 0}
+	r := ParallelMap(s, 3, func(i int) int {
+		time.Sleep(time.Duration(i) * time.Millisecond)
+		return i * 10
+	})
+	if !reflect.DeepEqual(r, []interface{}{50, 10, 40, 20, 30, 0}) {
+		t.Fatal()
+	}
+
+	if len(ParallelMap([]int{}, 0, func(i int) int { return i })) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestParallelMapWorkers(t *testing.T) {
+	var running, max int32
+	ParallelMap(RangeInt(0, 20, 1), 4, func(i int) int {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			m := atomic.LoadInt32(&max)
+			if n <= m || atomic.CompareAndSwapInt32(&max, m, n) {
+				break
+			}
+		}
+		time.Sleep(time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return i
+	})
+	if max > 4 {
+		t.Fatal(max)
+	}
+}
+
+func TestParallelForeach(t *testing.T) {
+	var sum int64
+	ParallelForeach([]int{1, 2, 3, 4}, 0, func(i int) {
+		atomic.AddInt64(&sum, int64(i))
+	})
+	if sum != 10 {
+		t.Fatal()
+	}
+}
+
+func TestParallelMapPanic(t *testing.T) {
+	defer func() {
+		p, ok := recover().(*WorkerPanic)
+		if !ok || p.Index != 2 || p.Value != "boom" {
+			t.Fatal(p)
+		}
+	}()
+	ParallelMap([]int{0, 1, 2, 3}, 2, func(i int) int {
+		if i == 2 {
+			panic("boom")
+		}
+		return i
+	})
+}
+
+func TestParallelForeachPanic(t *testing.T) {
+	defer func() {
+		if _, ok := recover().(*WorkerPanic); !ok {
+			t.Fatal()
+		}
+	}()
+	ParallelForeach([]int{0, 1, 2, 3}, 0, func(i int) {
+		panic(i)
+	})
+}
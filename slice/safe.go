@@ -0,0 +1,173 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"container/list"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/uestcer/utils/errors"
+)
+
+// Error codes of the errors returned by Safe and the Safe functions.
+const (
+	// An argument has a wrong type, e.g. i is not a slice, or f is not a
+	// func of the expected signature.
+	ErrCodeArgument = 1
+
+	// The callback panicked.
+	ErrCodeCallback = 2
+)
+
+// Call fn, and return the panic raised by fn as an Error, e.g. the panic of
+// a slice function on a wrong argument type. The stack trace of the Error
+// includes the panicking frames. Return nil if fn does not panic.
+// The code of the Error is ErrCodeArgument for the argument panics of the
+// slice functions called by fn, otherwise ErrCodeCallback, including the
+// argument panics of slice functions called inside a callback.
+// A callback panic with an error value is wrapped as the inner error.
+func Safe(fn func()) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = recoveredError(r)
+		}
+	}()
+	fn()
+	return nil
+}
+
+func recoveredError(r interface{}) errors.Error {
+	if p, ok := r.(callbackPanic); ok {
+		r = p.value
+	} else if msg, ok := r.(string); ok && strings.HasPrefix(msg, "utils/slice") {
+		return errors.NewByCode(ErrCodeArgument, msg)
+	}
+	if e, ok := r.(error); ok {
+		return errors.WrapByCode(ErrCodeCallback, e, "utils/slice: callback panicked")
+	}
+	return errors.NewfByCode(ErrCodeCallback, "utils/slice: callback panicked: %v", r)
+}
+
+// The panic of a callback called under Safe, raised again by the slice
+// function calling the callback, see call.
+type callbackPanic struct {
+	value interface{}
+}
+
+// Call the callback f with args.
+// A panic of f is raised again as is, or wrapped in a callbackPanic if the
+// call is under Safe, so Safe tells it from the panics on wrong arguments.
+// The panics of callers not using Safe keep their value.
+func call(f reflect.Value, args ...reflect.Value) []reflect.Value {
+	defer markCallbackPanic()
+	return f.Call(args)
+}
+
+// Raise the recovered callback panic again, see call.
+// The functions calling typed callbacks defer it after checking their
+// arguments, so their own argument panics are not marked.
+// NOTE: Must be deferred, recover only works in a deferred call.
+func markCallbackPanic() {
+	if r := recover(); r != nil {
+		if _, ok := r.(callbackPanic); !ok && underSafe() {
+			r = callbackPanic{r}
+		}
+		panic(r)
+	}
+}
+
+// The function name of Safe in stack traces.
+var safeName = runtime.FuncForPC(reflect.ValueOf(Safe).Pointer()).Name()
+
+// Check if Safe is a caller of the current function.
+func underSafe() bool {
+	pcs := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(2, pcs)
+		if n < len(pcs) {
+			pcs = pcs[:n]
+			break
+		}
+		pcs = make([]uintptr, 2*len(pcs))
+	}
+
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		if f.Function == safeName {
+			return true
+		}
+		if !more {
+			return false
+		}
+	}
+}
+
+// Same as Foreach, but return an error instead of panicking.
+func SafeForeach(i interface{}, f interface{}) error {
+	return Safe(func() {
+		Foreach(i, f)
+	})
+}
+
+// Same as Map, but return an error instead of panicking.
+func SafeMap(i interface{}, f interface{}) (result []interface{}, err error) {
+	err = Safe(func() {
+		result = Map(i, f)
+	})
+	return
+}
+
+// Same as Filter, but return an error instead of panicking.
+func SafeFilter(i interface{}, f interface{}) (result []interface{}, err error) {
+	err = Safe(func() {
+		result = Filter(i, f)
+	})
+	return
+}
+
+// Same as ToTypedSlice, but return an error instead of panicking.
+func SafeToTypedSlice(in []interface{}, sample interface{}) (result interface{}, err error) {
+	err = Safe(func() {
+		result = ToTypedSlice(in, sample)
+	})
+	return
+}
+
+// Same as FromList, but return an error instead of panicking.
+func SafeFromList(l *list.List, sample interface{}) (result interface{}, err error) {
+	err = Safe(func() {
+		result = FromList(l, sample)
+	})
+	return
+}
+
+// Same as Exist, but return an error instead of panicking.
+func SafeExist(i interface{}, f interface{}) (ok bool, err error) {
+	err = Safe(func() {
+		ok = Exist(i, f)
+	})
+	return
+}
+
+// Same as Index, but return an error instead of panicking.
+// The index is -1 on error.
+func SafeIndex(i interface{}, f interface{}) (index int, err error) {
+	index = -1
+	err = Safe(func() {
+		index = Index(i, f)
+	})
+	return
+}
+
+// Same as Find, but return an error instead of panicking.
+func SafeFind(i interface{}, f interface{}) (ok bool, v interface{}, err error) {
+	err = Safe(func() {
+		ok, v = Find(i, f)
+	})
+	return
+}
@@ -0,0 +1,176 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package slice
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/uestcer/utils/errors"
+)
+
+func TestSafe(t *testing.T) {
+	if Safe(func() {}) != nil {
+		t.Fatal()
+	}
+
+	err := Safe(func() { Foreach(1, func(i int) {}) })
+	e, ok := err.(errors.Error)
+	if !ok || e.Message() != "utils/slice: argument type is not slice, int." {
+		t.Fatal(err)
+	}
+	if strings.Index(e.Stack(), "reflectSlice") == -1 {
+		t.Fatalf("panicking frames missing in:\n%s", e.Stack())
+	}
+}
+
+func TestSafeMap(t *testing.T) {
+	r, err := SafeMap([]int{1, 2}, func(i int) int { return i * 10 })
+	if err != nil || !reflect.DeepEqual(r, []interface{}{10, 20}) {
+		t.Fatal()
+	}
+
+	r, err = SafeMap([]int{1, 2}, "not a func")
+	if r != nil || err == nil {
+		t.Fatal()
+	}
+}
+
+func TestSafeFilter(t *testing.T) {
+	r, err := SafeFilter([]int{1, 2}, func(i int) bool { return i > 1 })
+	if err != nil || !reflect.DeepEqual(r, []interface{}{2}) {
+		t.Fatal()
+	}
+
+	r, err = SafeFilter(map[int]int{}, func(i int) bool { return true })
+	if r != nil || errors.Message(err) != "utils/slice: argument type is not slice, map." {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeCodes(t *testing.T) {
+	// Not a slice.
+	err := SafeForeach("abc", func(s string) {})
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeArgument ||
+		e.Message() != "utils/slice: argument type is not slice, string." {
+		t.Fatal(err)
+	}
+
+	// Not a func.
+	_, err = SafeExist([]int{1}, 1)
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeArgument ||
+		e.Message() != "utils/slice: argument type is not func, int." {
+		t.Fatal(err)
+	}
+
+	// Bad signature.
+	i, err := SafeIndex([]int{1}, func(s string) bool { return true })
+	if e, ok := err.(errors.Error); i != -1 || !ok || e.Code() != ErrCodeArgument ||
+		e.Message() != "utils/slice.Index: predicate must be func(int) bool, got func(string) bool." {
+		t.Fatal(err)
+	}
+
+	// Panicking callback.
+	found, v, err := SafeFind([]int{1, 2}, func(i int) bool { panic("boom") })
+	if e, ok := err.(errors.Error); found || v != nil || !ok || e.Code() != ErrCodeCallback ||
+		e.Message() != "utils/slice: callback panicked: boom" {
+		t.Fatal(err)
+	}
+
+	// Panicking callback with an error value.
+	inner := errors.New("inner")
+	_, err = SafeMap([]int{1}, func(i int) int { panic(inner) })
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeCallback || e.Inner() != inner ||
+		!strings.Contains(e.Error(), "inner") {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeNestedCodes(t *testing.T) {
+	// A slice function misused inside a callback is a callback panic.
+	err := SafeForeach([]int{1}, func(i int) { Foreach(i, func(int) {}) })
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeCallback ||
+		errors.Message(e) != "utils/slice: callback panicked: utils/slice: argument type is not slice, int." {
+		t.Fatal(err)
+	}
+
+	// A foreign panic string with the package prefix is a callback panic.
+	err = SafeForeach([]int{1}, func(i int) { panic("utils/slice: fake") })
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeCallback {
+		t.Fatal(err)
+	}
+
+	// Safe inside a callback classifies the panics of its own calls.
+	Foreach([]int{1}, func(i int) {
+		err = SafeForeach(i, func(int) {})
+	})
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeArgument {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeNoError(t *testing.T) {
+	n := 0
+	if err := SafeForeach([]int{1, 2}, func(i int) { n += i }); err != nil || n != 3 {
+		t.Fatal()
+	}
+	if ok, err := SafeExist([]int{1, 2}, func(i int) bool { return i == 2 }); !ok || err != nil {
+		t.Fatal()
+	}
+	if i, err := SafeIndex([]int{1, 2}, func(i int) bool { return i == 2 }); i != 1 || err != nil {
+		t.Fatal()
+	}
+	if ok, v, err := SafeFind([]int{1, 2}, func(i int) bool { return i == 2 }); !ok || v != 2 || err != nil {
+		t.Fatal()
+	}
+}
+
+func TestSafeToTypedSlice(t *testing.T) {
+	r, err := SafeToTypedSlice([]interface{}{"a", 1}, []string{})
+	if r != nil || errors.Message(err) !=
+		"utils/slice: element 1, value type int is not assignable to element type string." {
+		t.Fatal(err)
+	}
+
+	r, err = SafeToTypedSlice([]interface{}{"a"}, []string{})
+	if err != nil || !reflect.DeepEqual(r, []string{"a"}) {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeFromList(t *testing.T) {
+	r, err := SafeFromList(ToList([]interface{}{1, "a"}), []int{})
+	if r != nil || errors.Message(err) !=
+		"utils/slice: element 1, value type string is not assignable to element type int." {
+		t.Fatal(err)
+	}
+}
+
+func TestSafeCallbackMarks(t *testing.T) {
+	// A slice function misused inside a typed callback is a callback panic.
+	err := Safe(func() {
+		Batch([]int{1, 2}, 1, func(b interface{}) error {
+			Foreach(b.([]int)[0], func(int) {})
+			return nil
+		})
+	})
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeCallback {
+		t.Fatal(err)
+	}
+
+	err = Safe(func() { Batch([]int{1}, 0, nil) })
+	if e, ok := err.(errors.Error); !ok || e.Code() != ErrCodeArgument {
+		t.Fatal(err)
+	}
+
+	// Without Safe, the panic values are kept.
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Fatal(r)
+		}
+	}()
+	Foreach([]int{1}, func(int) { panic("boom") })
+}
@@ -3,13 +3,22 @@
 // license that can be found in the LICENSE file.
 
 // Useful functions for handle slice.
+// Arrays and array pointers are accepted wherever a slice is, functions
+// returning a slice of the same type return a slice of the array element type.
 // NOTE: function will panic if the argument type is not
 // correct at runtime.
 package slice
 
 import (
 	"container/list"
+	"fmt"
+	"math"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/uestcer/utils/errors"
 )
 
 // New a list, and append the elements to list in order.
@@ -50,122 +59,1490 @@ func ToSlice(l *list.List) []interface{} {
 	return result
 }
 
+// Convert the elements to a new slice with the type of sample, e.g. the
+// result of Map or Filter to []string by slice.ToTypedSlice(r, []string(nil)).
+// nil elements become the zero value of pointer, interface, slice, map,
+// chan and func element types.
+// NOTE: Panic if sample is not a slice, or an element is not assignable to
+// the element type, the message has the index of the element.
+func ToTypedSlice(in []interface{}, sample interface{}) interface{} {
+	t := reflect.TypeOf(sample)
+	if t == nil || t.Kind() != reflect.Slice {
+		panic(fmt.Sprintf("utils/slice: sample type is not slice, %T.", sample))
+	}
+
+	result := reflect.MakeSlice(t, len(in), len(in))
+	for i, x := range in {
+		v, msg := elemValue(x, t.Elem())
+		if msg != "" {
+			panic(fmt.Sprintf("utils/slice: element %d, %s.", i, msg))
+		}
+		result.Index(i).Set(v)
+	}
+	return result.Interface()
+}
+
+// Convert the list elements to a new slice with the type of sample, the
+// inverse of ToList. Return an empty slice, if list is nil or empty.
+// Example: slice.FromList(slice.ToList(s), s) is equal to s
+// NOTE: Panic same as ToTypedSlice, the index is the position in the list.
+func FromList(l *list.List, sample interface{}) interface{} {
+	return ToTypedSlice(ToSlice(l), sample)
+}
+
+// Generate the ints from start to end (exclusive) by step.
+// Return an empty slice if end can't be reached in the step direction.
+// Example: slice.RangeInt(0, 5, 2) returns [0, 2, 4], slice.RangeInt(3, 0, -1) returns [3, 2, 1]
+// NOTE: Panic if step is 0.
+func RangeInt(start, end, step int) []int {
+	if step == 0 {
+		panic("utils/slice: step is 0.")
+	}
+
+	n := 0
+	if step > 0 && end > start {
+		n = (end - start + step - 1) / step
+	} else if step < 0 && end < start {
+		n = (start - end - step - 1) / -step
+	}
+
+	result := make([]int, n)
+	for i := range result {
+		result[i] = start + i*step
+	}
+	return result
+}
+
+// Generate the float64s from start to end (exclusive) by step.
+// Values are computed as start + i*step, so errors don't accumulate.
+// NOTE: Panic if step is 0.
+func RangeFloat64(start, end, step float64) []float64 {
+	if step == 0 {
+		panic("utils/slice: step is 0.")
+	}
+
+	n := 0
+	if (step > 0 && end > start) || (step < 0 && end < start) {
+		n = int(math.Ceil((end - start) / step))
+	}
+
+	result := make([]float64, n)
+	for i := range result {
+		result[i] = start + float64(i)*step
+	}
+	return result
+}
+
+// Generate a slice with n copies of value, with the type []T where T is
+// the type of value.
+// NOTE: Panic if value is nil, or n is negative.
+func Repeat(value interface{}, n int) interface{} {
+	if value == nil {
+		panic("utils/slice: value is nil.")
+	}
+	if n < 0 {
+		panic(fmt.Sprintf("utils/slice: count %d is negative.", n))
+	}
+
+	v := reflect.ValueOf(value)
+	result := reflect.MakeSlice(reflect.SliceOf(v.Type()), n, n)
+	for i := 0; i < n; i++ {
+		result.Index(i).Set(v)
+	}
+	return result.Interface()
+}
+
 // Traverse the slice, call function f by element in order.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
 func Foreach(i interface{}, f interface{}) {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkCallback("Foreach", f, v1.Type().Elem())
+
+	for i := 0; i < v1.Len(); i++ {
+		call(v2, v1.Index(i))
+	}
+}
+
+// Traverse the slice once, call every function in fns by element in order.
+// NOTE: Panic if i is not slice or slice pointer, any of fns is not a func
+// or func pointer taking one argument.
+func Tee(i interface{}, fns ...interface{}) {
+	v1 := reflectSlice(i)
+	v2 := make([]reflect.Value, len(fns))
+	for j, f := range fns {
+		v2[j] = checkCallback("Tee", f, v1.Type().Elem())
+	}
+
+	for i := 0; i < v1.Len(); i++ {
+		args := []reflect.Value{v1.Index(i)}
+		for _, f := range v2 {
+			call(f, args...)
+		}
+	}
+}
+
+// Same as Foreach, but f takes the index and the element, func(int, T).
+// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer,
+// or f does not take an int index first.
+func ForeachIndexed(i interface{}, f interface{}) {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("ForeachIndexed", "callback", f, signature{
+		in:     []reflect.Type{intType, v1.Type().Elem()},
+		anyOut: true,
+		hint:   "use Foreach for func(element)",
+	})
 
 	for i := 0; i < v1.Len(); i++ {
-		v2.Call([]reflect.Value{v1.Index(i)})
+		call(v2, reflect.ValueOf(i), v1.Index(i))
 	}
 }
 
+// Same as Map, but f takes the index and the element, func(int, T) R.
+// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer,
+// or f does not take an int index first.
+func MapIndexed(i interface{}, f interface{}) []interface{} {
+	return mapIndexed("MapIndexed", i, f)
+}
+
+// Alias of MapIndexed, f must be func(int, T) U.
+// Example: slice.MapIdx([]string{"a", "b"}, prefix) returns ["0:a", "1:b"]
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func MapIdx(i interface{}, f interface{}) []interface{} {
+	return mapIndexed("MapIdx", i, f)
+}
+
+// name is the exported function reported in the panic message.
+func mapIndexed(name string, i interface{}, f interface{}) []interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkFunc(name, "mapper", f, signature{
+		in:   []reflect.Type{intType, v1.Type().Elem()},
+		out:  []reflect.Type{nil},
+		hint: "use Map for func(element)",
+	})
+
+	result := make([]interface{}, v1.Len())
+	for i := 0; i < v1.Len(); i++ {
+		result[i] = call(v2, reflect.ValueOf(i), v1.Index(i))[0].Interface()
+	}
+	return result
+}
+
 // Map the slice to another slice, convert element by function f in order.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
 func Map(i interface{}, f interface{}) []interface{} {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkMapper("Map", f, v1.Type().Elem())
 
 	result := make([]interface{}, v1.Len())
 	for i := 0; i < v1.Len(); i++ {
-		result[i] = v2.Call([]reflect.Value{v1.Index(i)})[0].Interface()
+		result[i] = call(v2, v1.Index(i))[0].Interface()
 	}
 	return result
 }
 
+// Fold the elements in order, and return every intermediate value.
+// f takes the accumulated value and an element, func(A, T) A, the first
+// accumulated value is initial. Element k of the result is the fold of the
+// first k+1 elements.
+// Example: slice.Scan([]int{1, 2, 3}, 0, add) returns [1, 3, 6]
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func Scan(i interface{}, initial interface{}, f interface{}) []interface{} {
+	return scan("Scan", i, initial, f, false)
+}
+
+// Same as Scan, but the result starts with initial, so it has len+1 elements.
+// Example: slice.ScanInclusiveInitial([]int{1, 2, 3}, 0, add) returns [0, 1, 3, 6]
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func ScanInclusiveInitial(i interface{}, initial interface{}, f interface{}) []interface{} {
+	return scan("ScanInclusiveInitial", i, initial, f, true)
+}
+
+func scan(name string, i interface{}, initial interface{}, f interface{}, inclusive bool) []interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkAccumulator(name, f, v1.Type().Elem(), false)
+
+	acc := reflectElem(initial, v2.Type().In(0))
+	result := make([]interface{}, 0, v1.Len()+1)
+	if inclusive {
+		result = append(result, acc.Interface())
+	}
+	for i := 0; i < v1.Len(); i++ {
+		acc = call(v2, acc, v1.Index(i))[0]
+		result = append(result, acc.Interface())
+	}
+	return result
+}
+
+// Fold the slice into a single value from left to right, f is func(A, T) A.
+// The accumulated value is initial at first, and f is called with the
+// accumulated value and every element, from index 0 to n-1.
+// Example: slice.FoldLeftR([]string{"a", "b"}, "", concat) returns "ab"
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func FoldLeftR(i interface{}, initial interface{}, f interface{}) interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkAccumulator("FoldLeftR", f, v1.Type().Elem(), false)
+
+	acc := reflectElem(initial, v2.Type().In(0))
+	for i := 0; i < v1.Len(); i++ {
+		acc = call(v2, acc, v1.Index(i))[0]
+	}
+	return acc.Interface()
+}
+
+// Same as FoldLeftR, but from right to left, f is func(T, A) A.
+// f is called with every element and the accumulated value, from index n-1 to 0.
+// Example: slice.FoldRightR([]string{"a", "b"}, "", concat) returns "ba"
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func FoldRightR(i interface{}, initial interface{}, f interface{}) interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkAccumulator("FoldRightR", f, v1.Type().Elem(), true)
+
+	acc := reflectElem(initial, v2.Type().In(1))
+	for i := v1.Len() - 1; i >= 0; i-- {
+		acc = call(v2, v1.Index(i), acc)[0]
+	}
+	return acc.Interface()
+}
+
 // Check if the slice has element satisfy function f.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
 // Return true if slice has at least such one element, Otherwise false.
 func Exist(i interface{}, f interface{}) bool {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkPredicate("Exist", f, v1.Type().Elem())
 
 	for i := 0; i < v1.Len(); i++ {
-		if v2.Call([]reflect.Value{v1.Index(i)})[0].Bool() {
+		if call(v2, v1.Index(i))[0].Bool() {
 			return true
 		}
 	}
 	return false
 }
 
+// Replace every element by the result of function f in place, keeping the
+// slice type. f must be func(T) T, where T is the element type.
+// NOTE: Panic if i is not slice or slice pointer, i is an array passed by value,
+// f type is not func or func pointer, or f does not return the element type.
+func Apply(i interface{}, f interface{}) {
+	v1 := reflectMutableSlice(i)
+	v2 := checkFunc("Apply", "mapper", f, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{v1.Type().Elem()},
+	})
+
+	for i := 0; i < v1.Len(); i++ {
+		e := v1.Index(i)
+		e.Set(call(v2, e)[0])
+	}
+}
+
+// Same as Foreach, but f returns an error, func(T) error. Stop at the first
+// error, and return it wrapped with the index of the element, e.g. "element 3: ...".
+// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer,
+// or f does not return an error.
+func TryForeach(i interface{}, f interface{}) error {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("TryForeach", "callback", f, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{errorType},
+	})
+
+	for i := 0; i < v1.Len(); i++ {
+		out := call(v2, v1.Index(i))
+		if err := toError(out[0]); err != nil {
+			return errors.WrapfPreserveCode(err, "element %d:", i)
+		}
+	}
+	return nil
+}
+
+// Same as Map, but f returns a result and an error, func(T) (R, error).
+// Stop at the first error, discard the results, and return the error wrapped
+// with the index of the element, e.g. "element 3: ...".
+// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer,
+// or f does not return a result and an error.
+func TryMap(i interface{}, f interface{}) ([]interface{}, error) {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("TryMap", "mapper", f, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{nil, errorType},
+	})
+
+	result := make([]interface{}, v1.Len())
+	for i := 0; i < v1.Len(); i++ {
+		out := call(v2, v1.Index(i))
+		if err := toError(out[1]); err != nil {
+			return nil, errors.WrapfPreserveCode(err, "element %d:", i)
+		}
+		result[i] = out[0].Interface()
+	}
+	return result, nil
+}
+
+// Split the slice into consecutive batches of at most size elements, and call
+// f with every batch in order. A batch is a sub-slice of i with the same type,
+// its capacity is limited to its length, so appending to it does not overwrite
+// the next batch. Stop at the first error, and return it wrapped with the index
+// of the batch, e.g. "batch 3: ...".
+// NOTE: Panic if i is not slice or slice pointer, or size <= 0.
+func Batch(i interface{}, size int, f func(batch interface{}) error) error {
+	v := reflectSlice(i)
+	if size <= 0 {
+		panic(fmt.Sprintf("utils/slice: batch size %d is not positive.", size))
+	}
+	defer markCallbackPanic()
+
+	for from, n := 0, 0; from < v.Len(); from, n = from+size, n+1 {
+		to := clamp(from+size, 0, v.Len())
+		if err := f(v.Slice3(from, to, to).Interface()); err != nil {
+			return errors.WrapfPreserveCode(err, "batch %d:", n)
+		}
+	}
+	return nil
+}
+
+// Return the sliding windows of size elements advancing by one, so a slice
+// of n elements yields n-size+1 windows, and none if n < size.
+// Every window is a copy with the same type as i, so modifying a window
+// affects neither the input nor the overlapping windows.
+// Example: slice.Window([]int{1, 2, 3}, 2) returns [[1, 2], [2, 3]]
+// NOTE: Panic if i is not slice or slice pointer, or size <= 0.
+func Window(i interface{}, size int) []interface{} {
+	return WindowStep(i, size, 1)
+}
+
+// Same as Window, but the windows advance by step elements.
+// Trailing elements not filling a whole window are dropped.
+// Example: slice.WindowStep([]int{1, 2, 3, 4, 5}, 2, 2) returns [[1, 2], [3, 4]]
+// NOTE: Panic if i is not slice or slice pointer, size <= 0, or step <= 0.
+func WindowStep(i interface{}, size, step int) []interface{} {
+	v := reflectSlice(i)
+	if size <= 0 {
+		panic(fmt.Sprintf("utils/slice: window size %d is not positive.", size))
+	}
+	if step <= 0 {
+		panic(fmt.Sprintf("utils/slice: window step %d is not positive.", step))
+	}
+
+	result := make([]interface{}, 0)
+	for from := 0; from+size <= v.Len(); from += step {
+		result = append(result, copySlice(v, from, from+size))
+	}
+	return result
+}
+
+// Call f with every two adjacent elements in order, func(prev, curr T),
+// so f is called len-1 times, and never if the slice has less than two elements.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func Pairwise(i interface{}, f interface{}) {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("Pairwise", "callback", f, signature{
+		in:     []reflect.Type{v1.Type().Elem(), v1.Type().Elem()},
+		anyOut: true,
+	})
+
+	for i := 1; i < v1.Len(); i++ {
+		call(v2, v1.Index(i-1), v1.Index(i))
+	}
+}
+
+// Same as Pairwise, but collect the results of f, func(prev, curr T) R.
+// Example: slice.PairwiseMap([]int{1, 4, 9}, sub) returns the deltas [3, 5]
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func PairwiseMap(i interface{}, f interface{}) []interface{} {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("PairwiseMap", "mapper", f, signature{
+		in:  []reflect.Type{v1.Type().Elem(), v1.Type().Elem()},
+		out: []reflect.Type{nil},
+	})
+
+	result := make([]interface{}, 0)
+	for i := 1; i < v1.Len(); i++ {
+		result = append(result, call(v2, v1.Index(i-1), v1.Index(i))[0].Interface())
+	}
+	return result
+}
+
+// Check if all elements of the slice satisfy function f.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+// Return true if no element fails f, so an empty slice always returns true.
+func All(i interface{}, f interface{}) bool {
+	v1 := reflectSlice(i)
+	v2 := checkPredicate("All", f, v1.Type().Elem())
+
+	for i := 0; i < v1.Len(); i++ {
+		if !call(v2, v1.Index(i))[0].Bool() {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if no element of the slice satisfies function f, the negation of Exist.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+// Return true for an empty slice.
+func None(i interface{}, f interface{}) bool {
+	v1 := reflectSlice(i)
+	v2 := checkPredicate("None", f, v1.Type().Elem())
+
+	for i := 0; i < v1.Len(); i++ {
+		if call(v2, v1.Index(i))[0].Bool() {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the element with the greatest key returned by keyFn, func(T) K,
+// the first one if several have it. Return false if the slice is empty.
+// K must be an int, uint, float or string kind.
+// Example: slice.MaxBy(words, func(s string) int { return len(s) }) returns the longest word
+// NOTE: Panic if i is not slice or slice pointer, keyFn is not a func of the
+// expected signature, or K is not ordered.
+func MaxBy(i interface{}, keyFn interface{}) (interface{}, bool) {
+	return extremeBy("MaxBy", i, keyFn, 1)
+}
+
+// Same as MaxBy, but return the element with the least key.
+func MinBy(i interface{}, keyFn interface{}) (interface{}, bool) {
+	return extremeBy("MinBy", i, keyFn, -1)
+}
+
+// Return the first element whose key compares to all other keys as sign.
+func extremeBy(name string, i interface{}, keyFn interface{}, sign int) (interface{}, bool) {
+	v1 := reflectSlice(i)
+	v2 := checkFunc(name, "keyFn", keyFn, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{nil},
+	})
+	if k := v2.Type().Out(0); !isOrdered(k.Kind()) {
+		panic("utils/slice." + name + ": key type " + k.String() + " is not ordered.")
+	}
+	if v1.Len() == 0 {
+		return nil, false
+	}
+
+	best := 0
+	bestKey := call(v2, v1.Index(0))[0]
+	for i := 1; i < v1.Len(); i++ {
+		key := call(v2, v1.Index(i))[0]
+		if compareOrdered(key, bestKey) == sign {
+			best, bestKey = i, key
+		}
+	}
+	return v1.Index(best).Interface(), true
+}
+
+// Check if values of kind k are ordered by <.
+func isOrdered(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.String:
+		return true
+	}
+	return false
+}
+
+// Compare two values of the same ordered kind, return -1, 0 or 1.
+func compareOrdered(a, b reflect.Value) int {
+	var less, greater bool
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		less, greater = a.Int() < b.Int(), a.Int() > b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		less, greater = a.Uint() < b.Uint(), a.Uint() > b.Uint()
+	case reflect.Float32, reflect.Float64:
+		less, greater = a.Float() < b.Float(), a.Float() > b.Float()
+	case reflect.String:
+		less, greater = a.String() < b.String(), a.String() > b.String()
+	}
+	switch {
+	case less:
+		return -1
+	case greater:
+		return 1
+	}
+	return 0
+}
+
+// Count the elements satisfy function f.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func Count(i interface{}, f interface{}) int {
+	v1 := reflectSlice(i)
+	v2 := checkPredicate("Count", f, v1.Type().Elem())
+
+	n := 0
+	for i := 0; i < v1.Len(); i++ {
+		if call(v2, v1.Index(i))[0].Bool() {
+			n++
+		}
+	}
+	return n
+}
+
+// Count the elements equal to value.
+// Elements are compared by ==, or reflect.DeepEqual if not comparable.
+// NOTE: Panic if i is not slice or slice pointer.
+func CountValue(i interface{}, value interface{}) int {
+	v := reflectSlice(i)
+
+	n := 0
+	for i := 0; i < v.Len(); i++ {
+		if equal(v.Index(i).Interface(), value) {
+			n++
+		}
+	}
+	return n
+}
+
+// An element value and its number of occurrences, see Frequencies.
+type Frequency struct {
+	Value interface{}
+	Count int
+}
+
+// Count the occurrences of every distinct element, and return them sorted
+// by count in descending order. Ties keep the order of first occurrence.
+// Elements are compared by ==, or reflect.DeepEqual if not comparable.
+// NOTE: Panic if i is not slice or slice pointer.
+func Frequencies(i interface{}) []Frequency {
+	v := reflectSlice(i)
+
+	result := []Frequency{}
+	indexes := make(map[interface{}]int)
+	for i := 0; i < v.Len(); i++ {
+		e := v.Index(i).Interface()
+		if j, ok := frequencyIndex(result, indexes, e); ok {
+			result[j].Count++
+			continue
+		}
+		if isComparable(e) {
+			indexes[e] = len(result)
+		}
+		result = append(result, Frequency{e, 1})
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Count > result[j].Count
+	})
+	return result
+}
+
+// Find the index of e in frequencies, by the map for comparable values,
+// or a linear search otherwise.
+func frequencyIndex(frequencies []Frequency, indexes map[interface{}]int, e interface{}) (int, bool) {
+	if isComparable(e) {
+		j, ok := indexes[e]
+		return j, ok
+	}
+	for j, f := range frequencies {
+		if reflect.DeepEqual(f.Value, e) {
+			return j, true
+		}
+	}
+	return -1, false
+}
+
 // Filter element satisfy function f, then return a new slice.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
 // If no element satisfied, return an empty slice.
 func Filter(i interface{}, f interface{}) []interface{} {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkPredicate("Filter", f, v1.Type().Elem())
 
 	result := make([]interface{}, 0)
 	for i := 0; i < v1.Len(); i++ {
 		e := v1.Index(i)
-		if v2.Call([]reflect.Value{e})[0].Bool() {
+		if call(v2, e)[0].Bool() {
 			result = append(result, e.Interface())
 		}
 	}
 	return result
 }
 
-// Get first element index satisfy function f
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
-// Return -1, if no element satisfy.
-func Index(i interface{}, f interface{}) int {
+// Filter element not satisfy function f, then return a new slice with
+// the same type as i.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func Reject(i interface{}, f interface{}) interface{} {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkPredicate("Reject", f, v1.Type().Elem())
 
+	result := makeSlice(v1, 0)
 	for i := 0; i < v1.Len(); i++ {
 		e := v1.Index(i)
-		if v2.Call([]reflect.Value{e})[0].Bool() {
-			return i
+		if !call(v2, e)[0].Bool() {
+			result = reflect.Append(result, e)
 		}
 	}
-	return -1
+	return result.Interface()
+}
+
+// Remove elements satisfy function f in place, keeping the order of the others.
+// The vacated tail of the backing array is zeroed, so removed pointers can be
+// garbage collected. Return the number of removed elements.
+// NOTE: Panic if ptr is not slice pointer, or f is not a func of the expected signature.
+func RemoveIf(ptr interface{}, f interface{}) int {
+	p := reflect.ValueOf(ptr)
+	if p.Kind() != reflect.Ptr || p.Elem().Kind() != reflect.Slice {
+		panic("utils/slice: argument type is not slice pointer, " + p.Type().String() + ".")
+	}
+	v1 := p.Elem()
+	v2 := checkPredicate("RemoveIf", f, v1.Type().Elem())
+
+	n := 0
+	for i := 0; i < v1.Len(); i++ {
+		e := v1.Index(i)
+		if !call(v2, e)[0].Bool() {
+			v1.Index(n).Set(e)
+			n++
+		}
+	}
+
+	removed := v1.Len() - n
+	zero := reflect.Zero(v1.Type().Elem())
+	for i := n; i < v1.Len(); i++ {
+		v1.Index(i).Set(zero)
+	}
+	v1.SetLen(n)
+	return removed
+}
+
+// Get first element index satisfy function f
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+// Return -1, if no element satisfy.
+func Index(i interface{}, f interface{}) int {
+	v1 := reflectSlice(i)
+	return indexOf(v1, checkPredicate("Index", f, v1.Type().Elem()))
 }
 
 // Get first element index satisfy function f in reverse order.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
 // Return -1, if no element satisfy.
 func IndexLast(i interface{}, f interface{}) int {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	return lastIndexOf(v1, checkPredicate("IndexLast", f, v1.Type().Elem()))
+}
 
-	for i := v1.Len() - 1; i > 0; i-- {
-		e := v1.Index(i)
-		if v2.Call([]reflect.Value{e})[0].Bool() {
+// Find first element satisfy function f
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func Find(i interface{}, f interface{}) (bool, interface{}) {
+	v1 := reflectSlice(i)
+	_, e, ok := found(v1, indexOf(v1, checkPredicate("Find", f, v1.Type().Elem())))
+	return ok, e
+}
+
+// Find first element satisfy function f in reverse order.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func FindLast(i interface{}, f interface{}) (bool, interface{}) {
+	v1 := reflectSlice(i)
+	_, e, ok := found(v1, lastIndexOf(v1, checkPredicate("FindLast", f, v1.Type().Elem())))
+	return ok, e
+}
+
+// Find first element satisfy function f, return its index and the element.
+// Return (-1, nil, false), if no element satisfy.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func FindIndex(i interface{}, f interface{}) (int, interface{}, bool) {
+	v1 := reflectSlice(i)
+	return found(v1, indexOf(v1, checkPredicate("FindIndex", f, v1.Type().Elem())))
+}
+
+// Same as FindIndex, but in reverse order.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func FindLastIndex(i interface{}, f interface{}) (int, interface{}, bool) {
+	v1 := reflectSlice(i)
+	return found(v1, lastIndexOf(v1, checkPredicate("FindLastIndex", f, v1.Type().Elem())))
+}
+
+// Return the first index of v whose element satisfy predicate f, or -1.
+func indexOf(v, f reflect.Value) int {
+	for i := 0; i < v.Len(); i++ {
+		if call(f, v.Index(i))[0].Bool() {
 			return i
 		}
 	}
 	return -1
 }
 
-// Find first element satisfy function f
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
-func Find(i interface{}, f interface{}) (bool, interface{}) {
+// Return the last index of v whose element satisfy predicate f, or -1.
+func lastIndexOf(v, f reflect.Value) int {
+	for i := v.Len() - 1; i >= 0; i-- {
+		if call(f, v.Index(i))[0].Bool() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Return index i and the element of v at i, or (-1, nil, false) if i < 0.
+func found(v reflect.Value, i int) (int, interface{}, bool) {
+	if i < 0 {
+		return -1, nil, false
+	}
+	return i, v.Index(i).Interface(), true
+}
+
+// Copy elements [from, to) of v to a new slice with the same type as v.
+func copySlice(v reflect.Value, from, to int) interface{} {
+	result := makeSlice(v, to-from)
+	reflect.Copy(result, v.Slice(from, to))
+	return result.Interface()
+}
+
+// Return the length of the longest prefix of v satisfying f.
+func prefixLen(v, f reflect.Value) int {
+	for i := 0; i < v.Len(); i++ {
+		if !call(f, v.Index(i))[0].Bool() {
+			return i
+		}
+	}
+	return v.Len()
+}
+
+// Reflect every argument to reflect.Value of slice, see reflectSlice.
+// NOTE: Panic if an element type is not assignable to the first one's.
+func reflectSlices(slices []interface{}) []reflect.Value {
+	vs := make([]reflect.Value, len(slices))
+	for i, s := range slices {
+		vs[i] = reflectSlice(s)
+		t0, t := vs[0].Type().Elem(), vs[i].Type().Elem()
+		if !t.AssignableTo(t0) {
+			panic(fmt.Sprintf("utils/slice: argument %d element type %s is not assignable to %s.", i, t, t0))
+		}
+	}
+	return vs
+}
+
+// Copy elements of src to dst, src element type must be assignable to dst's.
+// Return the number of copied elements.
+func copyElems(dst, src reflect.Value) int {
+	if dst.Type().Elem() == src.Type().Elem() {
+		return reflect.Copy(dst, src)
+	}
+
+	n := src.Len()
+	if dst.Len() < n {
+		n = dst.Len()
+	}
+	for i := 0; i < n; i++ {
+		dst.Index(i).Set(src.Index(i))
+	}
+	return n
+}
+
+// Make a new slice with the same type as v, and length n.
+func makeSlice(v reflect.Value, n int) reflect.Value {
+	return reflect.MakeSlice(v.Type(), n, n)
+}
+
+// Return a random int in [0, n) from r, or the default source if r is nil.
+func randIntn(r *rand.Rand, n int) int {
+	if r == nil {
+		return rand.Intn(n)
+	}
+	return r.Intn(n)
+}
+
+// Limit n to [min, max].
+func clamp(n, min, max int) int {
+	if n < min {
+		return min
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+// A multiset of values, comparable values are counted in a map, others are
+// kept in a slice searched by reflect.DeepEqual.
+type valueSet struct {
+	values map[interface{}]int
+	others []interface{}
+}
+
+// Create a valueSet with the elements of slice v.
+func newValueSet(v reflect.Value) *valueSet {
+	set := &valueSet{values: make(map[interface{}]int, v.Len())}
+	for i := 0; i < v.Len(); i++ {
+		set.add(v.Index(i).Interface())
+	}
+	return set
+}
+
+func (s *valueSet) add(x interface{}) {
+	if isComparable(x) {
+		s.values[x]++
+	} else {
+		s.others = append(s.others, x)
+	}
+}
+
+func (s *valueSet) has(x interface{}) bool {
+	if isComparable(x) {
+		return s.values[x] > 0
+	}
+	return s.otherIndex(x) != -1
+}
+
+// Remove one occurrence of x, return false if x is not in the set.
+func (s *valueSet) remove(x interface{}) bool {
+	if isComparable(x) {
+		if s.values[x] == 0 {
+			return false
+		}
+		s.values[x]--
+		return true
+	}
+	j := s.otherIndex(x)
+	if j == -1 {
+		return false
+	}
+	s.others = append(s.others[:j], s.others[j+1:]...)
+	return true
+}
+
+func (s *valueSet) otherIndex(x interface{}) int {
+	for j, o := range s.others {
+		if reflect.DeepEqual(x, o) {
+			return j
+		}
+	}
+	return -1
+}
+
+// Check if x can be compared by == and used as a map key.
+// A comparable struct or array type may still hold a non-comparable dynamic
+// value in an interface field, so the value is checked, not only the type.
+func isComparable(x interface{}) bool {
+	return x == nil || comparableValue(reflect.ValueOf(x))
+}
+
+// Check if v and the dynamic values of its interfaces can be compared by ==.
+func comparableValue(v reflect.Value) bool {
+	if !v.Type().Comparable() {
+		return false
+	}
+	switch v.Kind() {
+	case reflect.Interface:
+		return v.IsNil() || comparableValue(v.Elem())
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !comparableValue(v.Field(i)) {
+				return false
+			}
+		}
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if !comparableValue(v.Index(i)) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Compare a and b by ==, fall back to reflect.DeepEqual if
+// either type is not comparable.
+func equal(a, b interface{}) bool {
+	if isComparable(a) && isComparable(b) {
+		return a == b
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// Return the first element, false if the slice is empty.
+// NOTE: Panic if i is not slice or slice pointer.
+func First(i interface{}) (interface{}, bool) {
+	v := reflectSlice(i)
+	if v.Len() == 0 {
+		return nil, false
+	}
+	return v.Index(0).Interface(), true
+}
+
+// Return the last element, false if the slice is empty.
+// NOTE: Panic if i is not slice or slice pointer.
+func Last(i interface{}) (interface{}, bool) {
+	v := reflectSlice(i)
+	if v.Len() == 0 {
+		return nil, false
+	}
+	return v.Index(v.Len() - 1).Interface(), true
+}
+
+// Return a copy of the first n elements, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer.
+// Return all elements if n > len, no element if n <= 0.
+func Take(i interface{}, n int) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, 0, clamp(n, 0, v.Len()))
+}
+
+// Return a copy of the elements after the first n, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer.
+// Return no element if n >= len, all elements if n <= 0.
+func Drop(i interface{}, n int) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, clamp(n, 0, v.Len()), v.Len())
+}
+
+// Return a copy of the last n elements, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer.
+// Return all elements if n > len, no element if n <= 0.
+func TakeLast(i interface{}, n int) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, v.Len()-clamp(n, 0, v.Len()), v.Len())
+}
+
+// Return a copy of the elements before the last n, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer.
+// Return no element if n >= len, all elements if n <= 0.
+func DropLast(i interface{}, n int) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, 0, v.Len()-clamp(n, 0, v.Len()))
+}
+
+// Return a copy of the longest prefix whose elements all satisfy function f,
+// with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func TakeWhile(i interface{}, f interface{}) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, 0, prefixLen(v, checkPredicate("TakeWhile", f, v.Type().Elem())))
+}
+
+// Return a copy of the elements starting at the first one not satisfying
+// function f, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func DropWhile(i interface{}, f interface{}) interface{} {
+	v := reflectSlice(i)
+	return copySlice(v, prefixLen(v, checkPredicate("DropWhile", f, v.Type().Elem())), v.Len())
+}
+
+// Split the slice at index, left holds the elements [0, index), right the rest.
+// An out of range index is clamped to the ends.
+// Example: slice.SplitAt([]int{1, 2, 3}, 1) returns [1] and [2, 3]
+// NOTE: Panic if i is not slice or slice pointer.
+func SplitAt(i interface{}, index int) (left, right []interface{}) {
+	v := reflectSlice(i)
+	index = clamp(index, 0, v.Len())
+	return interfaces(v, 0, index), interfaces(v, index, v.Len())
+}
+
+// Return elements [from, to) of v as []interface{}.
+func interfaces(v reflect.Value, from, to int) []interface{} {
+	result := make([]interface{}, to-from)
+	for i := range result {
+		result[i] = v.Index(from + i).Interface()
+	}
+	return result
+}
+
+// Return a new slice with the elements shifted left by n cyclically,
+// a negative n shifts right. n is reduced modulo the length.
+// Example: slice.Rotate([]int{1, 2, 3, 4}, 1) returns [2, 3, 4, 1]
+// NOTE: Panic if i is not slice or slice pointer.
+func Rotate(i interface{}, n int) []interface{} {
+	v := reflectSlice(i)
+
+	l := v.Len()
+	result := make([]interface{}, l)
+	if l == 0 {
+		return result
+	}
+
+	n = (n%l + l) % l
+	for i := 0; i < l; i++ {
+		result[i] = v.Index((i + n) % l).Interface()
+	}
+	return result
+}
+
+// Same as Rotate, but rotate the slice in place, moving the first k elements
+// to the end, by three reversals with reflect.Swapper.
+// Empty slices and multiples of the length are no-ops.
+// NOTE: Panic if i is not slice or slice pointer, or i is an array passed by value.
+func RotateInPlace(i interface{}, k int) {
+	v := reflectMutableSlice(i)
+	l := v.Len()
+	if l == 0 {
+		return
+	}
+	k = (k%l + l) % l
+	if k == 0 {
+		return
+	}
+
+	swap := reflect.Swapper(v.Interface())
+	reverse := func(from, to int) {
+		for to--; from < to; from, to = from+1, to-1 {
+			swap(from, to)
+		}
+	}
+	reverse(0, k)
+	reverse(k, l)
+	reverse(0, l)
+}
+
+// Same as RotateInPlace, but return a rotated copy with the same type as i.
+// The input slice is not modified.
+// NOTE: Panic if i is not slice or slice pointer.
+func Rotated(i interface{}, k int) interface{} {
+	v := reflectSlice(i)
+	result := copySlice(v, 0, v.Len())
+	RotateInPlace(result, k)
+	return result
+}
+
+// Shuffle the slice elements in place, using the default source of math/rand.
+// NOTE: Panic if i is not slice or slice pointer, or i is an array passed by value.
+func Shuffle(i interface{}) {
+	v := reflectMutableSlice(i)
+	rand.Shuffle(v.Len(), reflect.Swapper(v.Interface()))
+}
+
+// Shuffle the slice elements in place, using the random source r.
+// NOTE: Panic if i is not slice or slice pointer, or i is an array passed by value.
+func ShuffleWithRand(i interface{}, r *rand.Rand) {
+	v := reflectMutableSlice(i)
+	r.Shuffle(v.Len(), reflect.Swapper(v.Interface()))
+}
+
+// Return min(n, len) elements chosen uniformly at random without replacement,
+// with the same type as i. The input slice is not modified.
+// Use the default source of math/rand if r is nil.
+// NOTE: Panic if i is not slice or slice pointer.
+func Sample(i interface{}, n int, r *rand.Rand) interface{} {
+	v := reflectSlice(i)
+	n = clamp(n, 0, v.Len())
+
+	result := copySlice(v, 0, v.Len())
+	swap := reflect.Swapper(result)
+	for i := 0; i < n; i++ {
+		swap(i, i+randIntn(r, v.Len()-i))
+	}
+	return reflect.ValueOf(result).Slice(0, n).Interface()
+}
+
+// Return an element chosen uniformly at random, false if the slice is empty.
+// Use the default source of math/rand if r is nil.
+// NOTE: Panic if i is not slice or slice pointer.
+func SampleOne(i interface{}, r *rand.Rand) (interface{}, bool) {
+	v := reflectSlice(i)
+	if v.Len() == 0 {
+		return nil, false
+	}
+	return v.Index(randIntn(r, v.Len())).Interface(), true
+}
+
+// Return a new slice with the values inserted at index, with the same type as i.
+// Index len appends the values.
+// NOTE: Panic if i is not slice or slice pointer, index is out of range,
+// or a value is not assignable to the element type.
+func Insert(i interface{}, index int, values ...interface{}) interface{} {
+	v := reflectSlice(i)
+	if index < 0 || index > v.Len() {
+		panic(fmt.Sprintf("utils/slice: index %d out of range [0, %d].", index, v.Len()))
+	}
+
+	result := makeSlice(v, v.Len()+len(values))
+	reflect.Copy(result, v.Slice(0, index))
+	for j, value := range values {
+		result.Index(index + j).Set(reflectElem(value, v.Type().Elem()))
+	}
+	reflect.Copy(result.Slice(index+len(values), result.Len()), v.Slice(index, v.Len()))
+	return result.Interface()
+}
+
+// Return a new slice without the element at index, with the same type as i.
+// NOTE: Panic if i is not slice or slice pointer, or index is out of range.
+func RemoveAt(i interface{}, index int) interface{} {
+	v := reflectSlice(i)
+	if index < 0 || index >= v.Len() {
+		panic(fmt.Sprintf("utils/slice: index %d out of range [0, %d).", index, v.Len()))
+	}
+
+	result := makeSlice(v, v.Len()-1)
+	reflect.Copy(result, v.Slice(0, index))
+	reflect.Copy(result.Slice(index, result.Len()), v.Slice(index+1, v.Len()))
+	return result.Interface()
+}
+
+// Set every element of the slice to value in place.
+// nil sets the zero value of pointer, interface, slice, map, chan and func types.
+// NOTE: Panic if i is not slice or slice pointer, i is an array passed by value,
+// or value is not assignable to the element type.
+func Fill(i interface{}, value interface{}) {
+	v := reflectMutableSlice(i)
+	FillRange(v.Interface(), 0, v.Len(), value)
+}
+
+// Same as Fill, but only set the elements [from, to).
+// NOTE: Panic if i is not slice or slice pointer, i is an array passed by value,
+// the range is out of [0, len], from > to, or value is not assignable to the
+// element type.
+func FillRange(i interface{}, from, to int, value interface{}) {
+	v := reflectMutableSlice(i)
+	if from < 0 || to > v.Len() || from > to {
+		panic(fmt.Sprintf("utils/slice: range [%d, %d) out of range [0, %d].", from, to, v.Len()))
+	}
+
+	e := reflectElem(value, v.Type().Elem())
+	for i := from; i < to; i++ {
+		v.Index(i).Set(e)
+	}
+}
+
+// Render the elements by fmt.Sprint, and join them with sep.
+// Return "" if the slice is empty.
+// Example: slice.Join([]int{1, 2, 3}, ", ") returns "1, 2, 3"
+// NOTE: Panic if i is not slice or slice pointer.
+func Join(i interface{}, sep string) string {
+	v := reflectSlice(i)
+	strs := make([]string, v.Len())
+	for i := range strs {
+		strs[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return strings.Join(strs, sep)
+}
+
+// Same as Join, but render the elements by f, func(T) string.
+// NOTE: Panic if i is not slice or slice pointer, or f is not a func of the expected signature.
+func JoinFunc(i interface{}, sep string, f interface{}) string {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkFunc("JoinFunc", "formatter", f, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{reflect.TypeOf("")},
+	})
+
+	strs := make([]string, v1.Len())
+	for i := range strs {
+		strs[i] = call(v2, v1.Index(i))[0].String()
+	}
+	return strings.Join(strs, sep)
+}
+
+// Join the slices in order to a new slice, with the same type as the first one.
+// Return nil if no slice is given.
+// NOTE: Panic if an argument is not slice or slice pointer, or its element
+// type is not assignable to the element type of the first one.
+func Concat(slices ...interface{}) interface{} {
+	if len(slices) == 0 {
+		return nil
+	}
+
+	vs := reflectSlices(slices)
+	n := 0
+	for _, v := range vs {
+		n += v.Len()
+	}
+
+	result := makeSlice(vs[0], n)
+	n = 0
+	for _, v := range vs {
+		n += copyElems(result.Slice(n, result.Len()), v)
+	}
+	return result.Interface()
+}
+
+// Merge the slices round robin to a new slice, with the same type as the
+// first one. Every round takes one element from each slice not exhausted,
+// until all are exhausted. One slice results in a copy.
+// Return nil if no slice is given.
+// Example: slice.Interleave([]int{1, 2, 3}, []int{10}, []int{20, 30}) returns [1, 10, 20, 2, 30, 3]
+// NOTE: Panic if an argument is not slice or slice pointer, or its element
+// type is not assignable to the element type of the first one.
+func Interleave(slices ...interface{}) interface{} {
+	if len(slices) == 0 {
+		return nil
+	}
 
+	vs := reflectSlices(slices)
+	n, rounds := 0, 0
+	for _, v := range vs {
+		n += v.Len()
+		if v.Len() > rounds {
+			rounds = v.Len()
+		}
+	}
+
+	result := makeSlice(vs[0], n)
+	n = 0
+	for i := 0; i < rounds; i++ {
+		for _, v := range vs {
+			if i < v.Len() {
+				result.Index(n).Set(v.Index(i))
+				n++
+			}
+		}
+	}
+	return result.Interface()
+}
+
+// Combine two slices by calling f with the elements of the same index,
+// f is func(x, y) r. The result has the length of the shorter slice.
+// Example: slice.ZipWith([]int{1, 2}, []int{10, 20}, add) returns [11, 22]
+// NOTE: Panic if a or b is not slice or slice pointer, or f is not a func of the expected signature.
+func ZipWith(a, b interface{}, f interface{}) []interface{} {
+	v1, v2 := reflectSlice(a), reflectSlice(b)
+	v3 := checkFunc("ZipWith", "combiner", f, signature{
+		in:  []reflect.Type{v1.Type().Elem(), v2.Type().Elem()},
+		out: []reflect.Type{nil},
+	})
+
+	n := v1.Len()
+	if v2.Len() < n {
+		n = v2.Len()
+	}
+	result := make([]interface{}, n)
+	for i := 0; i < n; i++ {
+		result[i] = call(v3, v1.Index(i), v2.Index(i))[0].Interface()
+	}
+	return result
+}
+
+// Check if a and b have the same length and equal elements in order.
+// Elements are compared by ==, or reflect.DeepEqual if not comparable.
+// A nil slice is equal to an empty one, unlike reflect.DeepEqual.
+// NOTE: Panic if a or b is not slice or slice pointer.
+func Equal(a, b interface{}) bool {
+	v1, v2 := reflectSlice(a), reflectSlice(b)
+	if v1.Len() != v2.Len() {
+		return false
+	}
 	for i := 0; i < v1.Len(); i++ {
-		e := v1.Index(i)
-		if v2.Call([]reflect.Value{e})[0].Bool() {
-			return true, e.Interface()
+		if !equal(v1.Index(i).Interface(), v2.Index(i).Interface()) {
+			return false
 		}
 	}
-	return false, nil
+	return true
 }
 
-// Find first element satisfy function f in reverse order.
-// NOTE: Panic if i is not slice or slice pointer, f type is not func or func pointer.
-func FindLast(i interface{}, f interface{}) (bool, interface{}) {
+// Same as Equal, but elements are compared by eq, func(x A, y B) bool,
+// so a and b may have different element types.
+// NOTE: Panic if a or b is not slice or slice pointer, or eq is not a func of the expected signature.
+func EqualFunc(a, b interface{}, eq interface{}) bool {
+	v1, v2 := reflectSlice(a), reflectSlice(b)
+	v3 := checkFunc("EqualFunc", "eq", eq, signature{
+		in:  []reflect.Type{v1.Type().Elem(), v2.Type().Elem()},
+		out: []reflect.Type{boolType},
+	})
+
+	if v1.Len() != v2.Len() {
+		return false
+	}
+	for i := 0; i < v1.Len(); i++ {
+		if !call(v3, v1.Index(i), v2.Index(i))[0].Bool() {
+			return false
+		}
+	}
+	return true
+}
+
+// Check if b is a permutation of a, i.e. every element occurs as many times
+// in a as in b. Use it instead of Equal when the order is unspecified, e.g.
+// for the results of a map iteration or of concurrent workers.
+// Comparable elements are counted in a map, others are matched one by one by
+// reflect.DeepEqual.
+// NOTE: Panic if a or b is not slice or slice pointer.
+func EqualUnordered(a, b interface{}) bool {
+	v1, v2 := reflectSlice(a), reflectSlice(b)
+	if v1.Len() != v2.Len() {
+		return false
+	}
+
+	set := newValueSet(v1)
+	for i := 0; i < v2.Len(); i++ {
+		if !set.remove(v2.Index(i).Interface()) {
+			return false
+		}
+	}
+	return true
+}
+
+// Return the elements of a not in b, with the same type as a.
+// The order and duplicates of a are kept.
+// Elements are compared by ==, in O(len(a)+len(b)) time. Values of non-comparable
+// types are compared by reflect.DeepEqual, in quadratic time.
+// NOTE: Panic if a or b is not slice or slice pointer.
+func Difference(a, b interface{}) interface{} {
+	v1 := reflectSlice(a)
+	set := newValueSet(reflectSlice(b))
+
+	result := makeSlice(v1, 0)
+	for i := 0; i < v1.Len(); i++ {
+		if e := v1.Index(i); !set.has(e.Interface()) {
+			result = reflect.Append(result, e)
+		}
+	}
+	return result.Interface()
+}
+
+// Return the elements of a also in b, with the same type as a.
+// The order and duplicates of a are kept.
+// Elements are compared the same as in Difference.
+// NOTE: Panic if a or b is not slice or slice pointer.
+func Intersection(a, b interface{}) interface{} {
+	v1 := reflectSlice(a)
+	set := newValueSet(reflectSlice(b))
+
+	result := makeSlice(v1, 0)
+	for i := 0; i < v1.Len(); i++ {
+		if e := v1.Index(i); set.has(e.Interface()) {
+			result = reflect.Append(result, e)
+		}
+	}
+	return result.Interface()
+}
+
+// Return the elements of a followed by the elements of b, without duplicates,
+// with the same type as a. The first occurrence of each element is kept.
+// Elements are compared the same as in Difference.
+// NOTE: Panic if a or b is not slice or slice pointer, or the element type
+// of b is not assignable to the element type of a.
+func Union(a, b interface{}) interface{} {
+	vs := reflectSlices([]interface{}{a, b})
+	set := &valueSet{values: make(map[interface{}]int)}
+
+	result := makeSlice(vs[0], 0)
+	for _, v := range vs {
+		for i := 0; i < v.Len(); i++ {
+			e := v.Index(i)
+			if !set.has(e.Interface()) {
+				set.add(e.Interface())
+				result = reflect.Append(result, e)
+			}
+		}
+	}
+	return result.Interface()
+}
+
+// Return the elements with distinct keys returned by keyFn, func(T) K.
+// The first element for each key is kept, in the order of the slice.
+// Keys are compared the same as elements in Difference.
+// Example: slice.UniqBy(users, func(u User) string { return u.Email })
+// NOTE: Panic if i is not slice or slice pointer, or keyFn is not a func of the expected signature.
+func UniqBy(i interface{}, keyFn interface{}) []interface{} {
 	v1 := reflectSlice(i)
-	v2 := reflectFunc(f)
+	v2 := checkFunc("UniqBy", "keyFn", keyFn, signature{
+		in:  []reflect.Type{v1.Type().Elem()},
+		out: []reflect.Type{nil},
+	})
 
-	for i := v1.Len() - 1; i > 0; i-- {
+	seen := &valueSet{values: make(map[interface{}]int)}
+	result := make([]interface{}, 0)
+	for i := 0; i < v1.Len(); i++ {
 		e := v1.Index(i)
-		if v2.Call([]reflect.Value{e})[0].Bool() {
-			return true, e.Interface()
+		k := call(v2, e)[0].Interface()
+		if !seen.has(k) {
+			seen.add(k)
+			result = append(result, e.Interface())
 		}
 	}
-	return false, nil
+	return result
+}
+
+// Search target in a slice sorted in ascending order by function cmp, which
+// is func(elem E, target T) I, where I is a signed integer type, e.g. int.
+// cmp returns a negative number, zero or a positive number if elem sorts
+// before, equal to or after target, so target may have a different type,
+// e.g. a key of the elements.
+// Return the index of the first element not sorting before target, which is
+// the leftmost match if the slice has duplicates, or otherwise the position
+// target would be inserted at, and whether that element equals target.
+// Example: slice.BinarySearch([]int{1, 3, 3, 5}, 3, func(e, t int) int { return e - t }) returns 1, true
+// NOTE: The slice must be sorted by cmp, otherwise the result is undefined.
+// NOTE: Panic if i is not slice or slice pointer, or cmp is not a func of the expected signature.
+func BinarySearch(i interface{}, target interface{}, cmp interface{}) (int, bool) {
+	v1 := reflectSlice(i)
+	v2 := reflectFunc(cmp)
+	sig := signature{in: []reflect.Type{v1.Type().Elem(), nil}, out: []reflect.Type{nil}}
+	if !sig.match(v2.Type()) || !isSignedInt(v2.Type().Out(0)) {
+		sig.out = []reflect.Type{intType}
+		panicCallback("BinarySearch", "cmp", sig.String(), v2.Type(), "")
+	}
+	t := reflectElem(target, v2.Type().In(1))
+	compare := func(i int) int64 {
+		return call(v2, v1.Index(i), t)[0].Int()
+	}
+
+	n := sort.Search(v1.Len(), func(i int) bool { return compare(i) >= 0 })
+	return n, n < v1.Len() && compare(n) == 0
+}
+
+// Same as BinarySearch, but the slice is sorted by function less, which is
+// func(a, b T) bool and reports whether a sorts before b, so target has the
+// element type.
+// NOTE: The slice must be sorted by less, otherwise the result is undefined.
+// NOTE: Panic if i is not slice or slice pointer, or less is not a func of the expected signature.
+func BinarySearchLess(i interface{}, target interface{}, less interface{}) (int, bool) {
+	v1 := reflectSlice(i)
+	v2 := checkFunc("BinarySearchLess", "less", less, signature{
+		in:  []reflect.Type{v1.Type().Elem(), v1.Type().Elem()},
+		out: []reflect.Type{boolType},
+	})
+	t := reflectElem(target, v2.Type().In(0))
+
+	n := sort.Search(v1.Len(), func(i int) bool {
+		return !call(v2, v1.Index(i), t)[0].Bool()
+	})
+	if n < v1.Len() && !call(v2, t, v1.Index(n))[0].Bool() {
+		return n, true
+	}
+	return n, false
+}
+
+// Check if t is a signed integer type.
+func isSignedInt(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+// Same as BinarySearch, but for a slice of ints in ascending order.
+func BinarySearchInts(s []int, target int) (int, bool) {
+	n := sort.SearchInts(s, target)
+	return n, n < len(s) && s[n] == target
+}
+
+// Same as BinarySearch, but for a slice of strings in ascending order.
+func BinarySearchStrings(s []string, target string) (int, bool) {
+	n := sort.SearchStrings(s, target)
+	return n, n < len(s) && s[n] == target
+}
+
+// Reflect x to a reflect.Value of type t, nil becomes the zero value of
+// pointer, interface, slice, map, chan and func types.
+// NOTE: Panic if x is not assignable to t.
+func reflectElem(x interface{}, t reflect.Type) reflect.Value {
+	v, msg := elemValue(x, t)
+	if msg != "" {
+		panic("utils/slice: " + msg + ".")
+	}
+	return v
+}
+
+// Same as reflectElem, but return the reason instead of panicking,
+// empty if x is assignable to t.
+func elemValue(x interface{}, t reflect.Type) (reflect.Value, string) {
+	if x == nil {
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map,
+			reflect.Chan, reflect.Func:
+			return reflect.Zero(t), ""
+		}
+		return reflect.Value{}, "nil is not assignable to element type " + t.String()
+	}
+
+	v := reflect.ValueOf(x)
+	if !v.Type().AssignableTo(t) {
+		return reflect.Value{}, "value type " + v.Type().String() +
+			" is not assignable to element type " + t.String()
+	}
+	return v, ""
 }
 
 // Reflect i to reflect.Value, Elem() if value is PTR.
@@ -181,15 +1558,43 @@ func reflectFunc(f interface{}) reflect.Value {
 	return v
 }
 
+// Convert a reflected error result to error.
+func toError(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	return v.Interface().(error)
+}
+
 // Reflect i to reflect.Value, Elem() if value is PTR.
-// NOTE: Panic if the argument type is not slice or slice pointer.
+// An array or array pointer is converted to a slice of the whole array, which
+// shares the memory of the array if i is a pointer. An array passed by value
+// is copied, so in-place functions need an array pointer.
+// NOTE: Panic if the argument type is not slice, array or a pointer to them.
 func reflectSlice(i interface{}) reflect.Value {
 	v := reflect.ValueOf(i)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
 	}
+	if v.Kind() == reflect.Array {
+		if !v.CanAddr() {
+			a := reflect.New(v.Type()).Elem()
+			a.Set(v)
+			v = a
+		}
+		v = v.Slice(0, v.Len())
+	}
 	if v.Kind() != reflect.Slice {
 		panic("utils/slice: argument type is not slice, " + v.Kind().String() + ".")
 	}
 	return v
 }
+
+// Same as reflectSlice, for the functions modifying the elements in place.
+// NOTE: Panic if i is an array passed by value, whose copy would be modified.
+func reflectMutableSlice(i interface{}) reflect.Value {
+	if v := reflect.ValueOf(i); v.Kind() == reflect.Array {
+		panic("utils/slice: array must be passed by pointer, " + v.Type().String() + ".")
+	}
+	return reflectSlice(i)
+}
@@ -6,8 +6,14 @@ package slice
 
 import (
 	"container/list"
+	"fmt"
+	"math/rand"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
+
+	"github.com/uestcer/utils/errors"
 )
 
 func TestAsList(t *testing.T) {
@@ -53,6 +59,135 @@ func TestToSlice(t *testing.T) {
 	}
 }
 
+type foo struct {
+	name string
+}
+
+func TestToTypedSlice(t *testing.T) {
+	r := Filter([]string{"a", "bb", "c"}, func(s string) bool { return len(s) == 1 })
+	if s := ToTypedSlice(r, []string(nil)); !reflect.DeepEqual(s, []string{"a", "c"}) {
+		t.Fatal(s)
+	}
+
+	f := &foo{"f"}
+	s := ToTypedSlice([]interface{}{f, nil}, []*foo{}).([]*foo)
+	if len(s) != 2 || s[0] != f || s[1] != nil {
+		t.Fatal(s)
+	}
+
+	if s := ToTypedSlice([]interface{}{}, []int{}); !reflect.DeepEqual(s, []int{}) {
+		t.Fatal(s)
+	}
+}
+
+func TestToTypedSlicePanic(t *testing.T) {
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/slice: element 2, value type int is not assignable to element type string." {
+				t.Fatal(r)
+			}
+		}()
+		ToTypedSlice([]interface{}{"a", "b", 3}, []string{})
+	}()
+
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/slice: element 0, nil is not assignable to element type int." {
+				t.Fatal(r)
+			}
+		}()
+		ToTypedSlice([]interface{}{nil}, []int{})
+	}()
+
+	defer func() {
+		r := recover()
+		if r != "utils/slice: sample type is not slice, string." {
+			t.Fatal(r)
+		}
+	}()
+	ToTypedSlice([]interface{}{"a"}, "")
+}
+
+func TestFromList(t *testing.T) {
+	s := []foo{{"a"}, {"b"}, {"c"}}
+	r := FromList(ToList(s), s)
+	if !reflect.DeepEqual(r, s) {
+		t.Fatal(r)
+	}
+
+	if r := FromList(nil, []string(nil)); !reflect.DeepEqual(r, []string{}) {
+		t.Fatal(r)
+	}
+	if r := FromList(list.New(), []int{}); !reflect.DeepEqual(r, []int{}) {
+		t.Fatal(r)
+	}
+}
+
+func TestFromListPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice: element 1, value type string is not assignable to element type int." {
+			t.Fatal(r)
+		}
+	}()
+	FromList(AsList(1, "2"), []int{})
+}
+
+func TestRangeInt(t *testing.T) {
+	if !reflect.DeepEqual(RangeInt(0, 5, 1), []int{0, 1, 2, 3, 4}) ||
+		!reflect.DeepEqual(RangeInt(0, 5, 2), []int{0, 2, 4}) ||
+		!reflect.DeepEqual(RangeInt(0, 6, 2), []int{0, 2, 4}) ||
+		!reflect.DeepEqual(RangeInt(3, 0, -1), []int{3, 2, 1}) ||
+		!reflect.DeepEqual(RangeInt(10, -1, -5), []int{10, 5, 0}) {
+		t.Fatal()
+	}
+
+	if len(RangeInt(0, 0, 1)) != 0 || len(RangeInt(0, 5, -1)) != 0 ||
+		len(RangeInt(5, 0, 1)) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestRangeIntPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	RangeInt(0, 5, 0)
+}
+
+func TestRangeFloat64(t *testing.T) {
+	if !reflect.DeepEqual(RangeFloat64(0, 1, 0.25), []float64{0, 0.25, 0.5, 0.75}) ||
+		!reflect.DeepEqual(RangeFloat64(1, 0, -0.5), []float64{1, 0.5}) ||
+		len(RangeFloat64(0, 1, -1)) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestRepeat(t *testing.T) {
+	type point struct {
+		x, y int
+	}
+
+	if !reflect.DeepEqual(Repeat("a", 3), []string{"a", "a", "a"}) ||
+		!reflect.DeepEqual(Repeat(point{1, 2}, 2), []point{{1, 2}, {1, 2}}) ||
+		!reflect.DeepEqual(Repeat(1, 0), []int{}) {
+		t.Fatal()
+	}
+}
+
+func TestRepeatPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	Repeat(1, -1)
+}
+
 func TestForeach(t *testing.T) {
 	sum := 0
 	Foreach([]int{1, 2, 3, 4}, func(i int) { sum += i })
@@ -61,6 +196,91 @@ func TestForeach(t *testing.T) {
 	}
 }
 
+func TestForeachIndexed(t *testing.T) {
+	parts := []string{}
+	ForeachIndexed([]string{"a", "b"}, func(i int, s string) {
+		parts = append(parts, fmt.Sprintf("%d:%s", i, s))
+	})
+	if strings.Join(parts, " ") != "0:a 1:b" {
+		t.Fatal()
+	}
+}
+
+func TestMapIndexed(t *testing.T) {
+	r := MapIndexed([]string{"a", "b"}, func(i int, s string) string {
+		return fmt.Sprintf("%d:%s", i, s)
+	})
+	if !reflect.DeepEqual(r, []interface{}{"0:a", "1:b"}) {
+		t.Fatal()
+	}
+}
+
+func TestMapIdx(t *testing.T) {
+	r := MapIdx([]string{"a", "b"}, func(i int, s string) string {
+		return fmt.Sprint(i, ":", s)
+	})
+	if !reflect.DeepEqual(r, []interface{}{"0:a", "1:b"}) {
+		t.Fatal()
+	}
+}
+
+func TestMapIdxPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.MapIdx: mapper must be func(int, string) R, got func(int, int) int." {
+			t.Fatal(r)
+		}
+	}()
+	MapIdx([]string{"a"}, func(i, j int) int { return i })
+}
+
+func TestMapIndexedPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.MapIndexed: mapper must be func(int, string) R, "+
+			"got func(string) string, use Map for func(element)." {
+			t.Fatal(r)
+		}
+	}()
+	MapIndexed([]string{"a"}, func(s string) string { return s })
+}
+
+func TestForeachIndexedPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.ForeachIndexed: callback must be func(int, string), got func(string, int)." {
+			t.Fatal(r)
+		}
+	}()
+	ForeachIndexed([]string{"a"}, func(s string, i int) {})
+}
+
+func TestTee(t *testing.T) {
+	sum := 0
+	calls := []string{}
+	Tee([]int{1, 2, 3},
+		func(i int) { sum += i },
+		func(i int) { calls = append(calls, fmt.Sprint("b", i)) },
+		func(i int) { calls = append(calls, fmt.Sprint("c", i)) })
+
+	if sum != 6 {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(calls, []string{"b1", "c1", "b2", "c2", "b3", "c3"}) {
+		t.Fatal()
+	}
+}
+
+func TestTeePanic(t *testing.T) {
+	calls := 0
+	defer func() {
+		if recover() == nil || calls != 0 {
+			t.Fatal()
+		}
+	}()
+	Tee([]int{1, 2, 3}, func(i int) { calls++ }, func(i, j int) {})
+}
+
 func TestMap(t *testing.T) {
 	r := Map([]int{1, 2, 3, 4}, func(i int) int { return i * 100 })
 	if !reflect.DeepEqual(r, []interface{}{100, 200, 300, 400}) {
@@ -68,6 +288,85 @@ func TestMap(t *testing.T) {
 	}
 }
 
+func TestApply(t *testing.T) {
+	s := []int{1, 2, 3}
+	Apply(s, func(i int) int { return i * 10 })
+	if !reflect.DeepEqual(s, []int{10, 20, 30}) {
+		t.Fatal()
+	}
+
+	p := &[]string{"a", "b"}
+	Apply(p, func(s string) string { return s + s })
+	if !reflect.DeepEqual(*p, []string{"aa", "bb"}) {
+		t.Fatal()
+	}
+}
+
+func TestApplyPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.Apply: mapper must be func(int) int, got func(int) string." {
+			t.Fatal(r)
+		}
+	}()
+	Apply([]int{1}, func(i int) string { return "" })
+}
+
+func TestFoldR(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	l := FoldLeftR(s, ">", func(acc string, v string) string { return acc + v })
+	r := FoldRightR(s, ">", func(v string, acc string) string { return acc + v })
+	if l != ">abc" || r != ">cba" {
+		t.Fatal(l, r)
+	}
+
+	if FoldLeftR([]int{}, 7, func(acc, v int) int { return 0 }) != 7 {
+		t.Fatal()
+	}
+}
+
+func TestFoldRPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.FoldRightR: accumulator must be func(int, A) A, got func(string, int) string." {
+			t.Fatal(r)
+		}
+	}()
+	FoldRightR([]int{1}, "", func(acc string, v int) string { return acc })
+}
+
+func TestScan(t *testing.T) {
+	r := Scan([]int{1, 2, 3}, 0, func(acc, i int) int { return acc + i })
+	if !reflect.DeepEqual(r, []interface{}{1, 3, 6}) {
+		t.Fatal()
+	}
+
+	r = Scan([]int{}, 0, func(acc, i int) int { return acc + i })
+	if len(r) != 0 {
+		t.Fatal()
+	}
+
+	r = Scan([]string{"a", "b", "c"}, ">", func(acc, s string) string { return acc + s })
+	if !reflect.DeepEqual(r, []interface{}{">a", ">ab", ">abc"}) {
+		t.Fatal(r)
+	}
+}
+
+func TestScanInclusiveInitial(t *testing.T) {
+	add := func(acc, i int) int { return acc + i }
+	if r := ScanInclusiveInitial([]int{1, 2, 3}, 10, add); !reflect.DeepEqual(r, []interface{}{10, 11, 13, 16}) {
+		t.Fatal(r)
+	}
+	if r := ScanInclusiveInitial([]int{}, 10, add); !reflect.DeepEqual(r, []interface{}{10}) {
+		t.Fatal(r)
+	}
+
+	r := ScanInclusiveInitial([]string{"a", "b"}, "", func(acc, s string) string { return acc + s })
+	if !reflect.DeepEqual(r, []interface{}{"", "a", "ab"}) {
+		t.Fatal(r)
+	}
+}
+
 func TestExist(t *testing.T) {
 	r1 := Exist([]int{1, 2, 3, 4}, func(i int) bool { return i%3 == 0 })
 	r2 := Exist([]int{1, 2, 3, 4}, func(i int) bool { return i%5 == 0 })
@@ -79,6 +378,312 @@ func TestExist(t *testing.T) {
 	}
 }
 
+func TestTryForeach(t *testing.T) {
+	sum := 0
+	err := TryForeach([]int{1, 2, 3}, func(i int) error {
+		sum += i
+		return nil
+	})
+	if err != nil || sum != 6 {
+		t.Fatal()
+	}
+
+	visited := []int{}
+	err = TryForeach([]int{1, 2, 3, 4}, func(i int) error {
+		visited = append(visited, i)
+		if i == 3 {
+			return fmt.Errorf("bad value %d", i)
+		}
+		return nil
+	})
+	if err == nil || errors.Message(err) != "element 2: bad value 3" {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(visited, []int{1, 2, 3}) {
+		t.Fatal()
+	}
+
+	if TryForeach([]int(nil), func(i int) error { return nil }) != nil {
+		t.Fatal()
+	}
+}
+
+func TestTryMap(t *testing.T) {
+	r, err := TryMap([]int{1, 2, 3}, func(i int) (string, error) {
+		return fmt.Sprint(i), nil
+	})
+	if err != nil || !reflect.DeepEqual(r, []interface{}{"1", "2", "3"}) {
+		t.Fatal()
+	}
+
+	r, err = TryMap([]int{1, 2, 3}, func(i int) (string, error) {
+		if i == 2 {
+			return "", errors.NewByCode(400, "bad value")
+		}
+		return fmt.Sprint(i), nil
+	})
+	if r != nil || errors.Message(err) != "element 1: bad value" {
+		t.Fatal(err)
+	}
+	if err.(errors.Error).Code() != 400 {
+		t.Fatal()
+	}
+
+	r, err = TryMap([]int(nil), func(i int) (string, error) { return "", nil })
+	if err != nil || len(r) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestTryMapPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.TryMap: mapper must be func(int) (R, error), got func(int) string." {
+			t.Fatal(r)
+		}
+	}()
+	TryMap([]int{1}, func(i int) string { return "" })
+}
+
+func TestBatch(t *testing.T) {
+	batches := [][]int{}
+	err := Batch([]int{1, 2, 3, 4, 5}, 2, func(batch interface{}) error {
+		batches = append(batches, batch.([]int))
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(batches, [][]int{{1, 2}, {3, 4}, {5}}) {
+		t.Fatal()
+	}
+
+	calls := 0
+	err = Batch([]int{1, 2, 3, 4, 5}, 2, func(batch interface{}) error {
+		calls++
+		if batch.([]int)[0] == 3 {
+			return fmt.Errorf("bad batch")
+		}
+		return nil
+	})
+	if errors.Message(err) != "batch 1: bad batch" || calls != 2 {
+		t.Fatal(err)
+	}
+
+	if Batch([]int{}, 2, func(batch interface{}) error { t.Fatal(); return nil }) != nil {
+		t.Fatal()
+	}
+}
+
+func TestBatchAppend(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	Batch(s, 2, func(batch interface{}) error {
+		_ = append(batch.([]int), 100)
+		return nil
+	})
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+}
+
+func TestBatchPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	Batch([]int{1}, 0, func(batch interface{}) error { return nil })
+}
+
+func TestWindow(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if r := Window(s, 2); !reflect.DeepEqual(r, []interface{}{[]int{1, 2}, []int{2, 3}, []int{3, 4}}) {
+		t.Fatal(r)
+	}
+	if r := Window(s, 4); !reflect.DeepEqual(r, []interface{}{[]int{1, 2, 3, 4}}) {
+		t.Fatal(r)
+	}
+	if r := Window(s, 5); len(r) != 0 {
+		t.Fatal(r)
+	}
+
+	if r := WindowStep([]int{1, 2, 3, 4, 5}, 2, 2); !reflect.DeepEqual(r, []interface{}{[]int{1, 2}, []int{3, 4}}) {
+		t.Fatal(r)
+	}
+	if r := WindowStep([]int{1, 2, 3, 4, 5}, 1, 3); !reflect.DeepEqual(r, []interface{}{[]int{1}, []int{4}}) {
+		t.Fatal(r)
+	}
+
+	// The windows are copies.
+	r := Window(s, 2)
+	r[0].([]int)[1] = 100
+	if s[1] != 2 || r[1].([]int)[0] != 2 {
+		t.Fatal(s, r)
+	}
+}
+
+func TestWindowPanic(t *testing.T) {
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/slice: window size 0 is not positive." {
+				t.Fatal(r)
+			}
+		}()
+		Window([]int{1}, 0)
+	}()
+
+	defer func() {
+		r := recover()
+		if r != "utils/slice: window step -1 is not positive." {
+			t.Fatal(r)
+		}
+	}()
+	WindowStep([]int{1}, 1, -1)
+}
+
+func TestPairwise(t *testing.T) {
+	pairs := []string{}
+	Pairwise([]string{"a", "b", "c"}, func(prev, curr string) { pairs = append(pairs, prev+curr) })
+	if !reflect.DeepEqual(pairs, []string{"ab", "bc"}) {
+		t.Fatal(pairs)
+	}
+
+	calls := 0
+	Pairwise([]int{1}, func(prev, curr int) { calls++ })
+	Pairwise([]int{}, func(prev, curr int) { calls++ })
+	if calls != 0 {
+		t.Fatal(calls)
+	}
+}
+
+func TestPairwiseMap(t *testing.T) {
+	diff := func(prev, curr int) int { return curr - prev }
+	if r := PairwiseMap([]int{1, 4, 9, 16}, diff); !reflect.DeepEqual(r, []interface{}{3, 5, 7}) {
+		t.Fatal(r)
+	}
+	if r := PairwiseMap([]int{1}, diff); !reflect.DeepEqual(r, []interface{}{}) {
+		t.Fatal(r)
+	}
+}
+
+func TestPairwisePanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.PairwiseMap: mapper must be func(int, int) R, got func(int) int." {
+			t.Fatal(r)
+		}
+	}()
+	PairwiseMap([]int{1, 2}, func(i int) int { return i })
+}
+
+func TestAll(t *testing.T) {
+	r1 := All([]int{2, 4, 6}, func(i int) bool { return i%2 == 0 })
+	r2 := All([]int{2, 3, 6}, func(i int) bool { return i%2 == 0 })
+	if r1 == false || r2 == true {
+		t.Fatal()
+	}
+
+	calls := 0
+	All([]int{1, 2, 3}, func(i int) bool { calls++; return false })
+	if calls != 1 {
+		t.Fatal()
+	}
+
+	// Vacuous truth: an empty slice has no counterexample.
+	if !All([]int{}, func(i int) bool { return false }) {
+		t.Fatal()
+	}
+}
+
+func TestNone(t *testing.T) {
+	r1 := None([]int{1, 3, 5}, func(i int) bool { return i%2 == 0 })
+	r2 := None([]int{1, 2, 5}, func(i int) bool { return i%2 == 0 })
+	if r1 == false || r2 == true {
+		t.Fatal()
+	}
+
+	if !None([]int{}, func(i int) bool { return true }) {
+		t.Fatal()
+	}
+}
+
+func TestMaxMinBy(t *testing.T) {
+	words := []string{"go", "gopher", "is", "golang"}
+	length := func(s string) int { return len(s) }
+	if v, ok := MaxBy(words, length); !ok || v != "gopher" {
+		t.Fatal(v, ok)
+	}
+	if v, ok := MinBy(words, length); !ok || v != "go" {
+		t.Fatal(v, ok)
+	}
+
+	users := []user{{"b", "x"}, {"a", "y"}, {"c", "z"}}
+	if v, ok := MinBy(users, func(u user) string { return u.name }); !ok || v != users[1] {
+		t.Fatal(v, ok)
+	}
+	if v, ok := MaxBy([]float64{1.5, -2, 3.25}, func(f float64) float64 { return -f }); !ok || v != -2.0 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := MaxBy([]int{}, func(i int) uint { return uint(i) }); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+}
+
+func TestMaxByPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.MaxBy: key type []int is not ordered." {
+			t.Fatal(r)
+		}
+	}()
+	MaxBy([]int{1}, func(i int) []int { return nil })
+}
+
+func TestCount(t *testing.T) {
+	n1 := Count([]int{1, 2, 3, 4, 6}, func(i int) bool { return i%3 == 0 })
+	n2 := Count([]int{1, 2, 3, 4}, func(i int) bool { return i%5 == 0 })
+	n3 := Count([]int{1, 2, 3, 4}, func(i int) bool { return i > 0 })
+	if n1 != 2 || n2 != 0 || n3 != 4 {
+		t.Fatal()
+	}
+}
+
+func TestCountValue(t *testing.T) {
+	type point struct {
+		x, y int
+	}
+	type tags struct {
+		names []string
+	}
+
+	if CountValue([]string{"a", "b", "a"}, "a") != 2 ||
+		CountValue([]string{"a", "b", "a"}, "c") != 0 {
+		t.Fatal()
+	}
+	if CountValue([]point{{1, 2}, {2, 1}, {1, 2}}, point{1, 2}) != 2 {
+		t.Fatal()
+	}
+	if CountValue([]tags{{[]string{"x"}}, {nil}}, tags{[]string{"x"}}) != 1 {
+		t.Fatal()
+	}
+}
+
+func TestFrequencies(t *testing.T) {
+	words := strings.Fields("the cat and the dog and the bird")
+	r := Frequencies(words)
+	expected := []Frequency{{"the", 3}, {"and", 2}, {"cat", 1}, {"dog", 1}, {"bird", 1}}
+	if !reflect.DeepEqual(r, expected) {
+		t.Fatal(r)
+	}
+
+	r = Frequencies([][]int{{1}, {2}, {2}})
+	if !reflect.DeepEqual(r, []Frequency{{[]int{2}, 2}, {[]int{1}, 1}}) {
+		t.Fatal(r)
+	}
+
+	if len(Frequencies([]int{})) != 0 {
+		t.Fatal()
+	}
+}
+
 func TestFilter(t *testing.T) {
 	rs := Filter([]int{1, 2, 3, 4}, func(i int) bool { return i%2 == 0 })
 	if !reflect.DeepEqual([]interface{}{2, 4}, rs) {
@@ -86,6 +691,41 @@ func TestFilter(t *testing.T) {
 	}
 }
 
+func TestReject(t *testing.T) {
+	rs := Reject([]int{1, 2, 3, 4}, func(i int) bool { return i%2 == 0 })
+	if !reflect.DeepEqual([]int{1, 3}, rs) {
+		t.Fatal()
+	}
+}
+
+func TestRemoveIf(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	n := RemoveIf(&s, func(i int) bool { return i%2 == 0 })
+	if n != 2 || !reflect.DeepEqual(s, []int{1, 3, 5}) {
+		t.Fatal()
+	}
+
+	a, b, c := 1, 2, 3
+	ps := []*int{&a, &b, &c}
+	backing := ps[:3]
+	n = RemoveIf(&ps, func(p *int) bool { return *p < 3 })
+	if n != 2 || len(ps) != 1 || ps[0] != &c {
+		t.Fatal()
+	}
+	if backing[1] != nil || backing[2] != nil {
+		t.Fatal()
+	}
+}
+
+func TestRemoveIfPanic(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	RemoveIf([]int{1, 2}, func(i int) bool { return true })
+}
+
 func TestIndex(t *testing.T) {
 	i1 := Index([]int{1, 2, 3, 4, 6}, func(i int) bool { return i%3 == 0 })
 	i2 := Index([]int{1, 2, 3, 4}, func(i int) bool { return i%5 == 0 })
@@ -104,6 +744,16 @@ func TestIndexLast(t *testing.T) {
 	}
 }
 
+func TestIndexLastFirstElement(t *testing.T) {
+	isMultipleOf3 := func(i int) bool { return i%3 == 0 }
+	if IndexLast([]int{3, 1, 2}, isMultipleOf3) != 0 ||
+		IndexLast([]int{3}, isMultipleOf3) != 0 ||
+		IndexLast([]int{1}, isMultipleOf3) != -1 ||
+		IndexLast([]int{}, isMultipleOf3) != -1 {
+		t.Fatal()
+	}
+}
+
 func TestFind(t *testing.T) {
 	ok1, r1 := Find([]int{1, 2, 3, 4, 6}, func(i int) bool { return i%3 == 0 })
 	ok2, _ := Find([]int{1, 2, 3, 4}, func(i int) bool { return i%5 == 0 })
@@ -129,3 +779,796 @@ func TestFindLast(t *testing.T) {
 		t.Fatal()
 	}
 }
+
+func TestFindLastFirstElement(t *testing.T) {
+	isMultipleOf3 := func(i int) bool { return i%3 == 0 }
+
+	ok1, r1 := FindLast([]int{3, 1, 2}, isMultipleOf3)
+	ok2, r2 := FindLast([]int{3}, isMultipleOf3)
+	ok3, _ := FindLast([]int{1}, isMultipleOf3)
+	ok4, _ := FindLast([]int{}, isMultipleOf3)
+	if !ok1 || r1 != 3 || !ok2 || r2 != 3 || ok3 || ok4 {
+		t.Fatal()
+	}
+}
+
+func TestFindIndex(t *testing.T) {
+	even := func(i int) bool { return i%2 == 0 }
+	s := []int{2, 3, 4, 5, 4, 6}
+
+	if i, v, ok := FindIndex(s, even); i != 0 || v != 2 || !ok {
+		t.Fatal(i, v, ok)
+	}
+	if i, v, ok := FindLastIndex(s, even); i != 5 || v != 6 || !ok {
+		t.Fatal(i, v, ok)
+	}
+	if i, v, ok := FindIndex(s, func(i int) bool { return i > 5 }); i != 5 || v != 6 || !ok {
+		t.Fatal(i, v, ok)
+	}
+	if i, v, ok := FindLastIndex(s, func(i int) bool { return i < 3 }); i != 0 || v != 2 || !ok {
+		t.Fatal(i, v, ok)
+	}
+	if i, v, ok := FindIndex(s, func(i int) bool { return i > 9 }); i != -1 || v != nil || ok {
+		t.Fatal(i, v, ok)
+	}
+	if i, v, ok := FindLastIndex([]int{}, even); i != -1 || v != nil || ok {
+		t.Fatal(i, v, ok)
+	}
+
+	// Duplicated matches.
+	four := func(i int) bool { return i == 4 }
+	if i, _, _ := FindIndex(s, four); i != 2 {
+		t.Fatal(i)
+	}
+	if i, _, _ := FindLastIndex(s, four); i != 4 {
+		t.Fatal(i)
+	}
+}
+
+func TestFirstLast(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	if v, ok := First(s); !ok || v != "a" {
+		t.Fatal(v, ok)
+	}
+	if v, ok := Last(s); !ok || v != "c" {
+		t.Fatal(v, ok)
+	}
+	if v, ok := Last(&[1]int{7}); !ok || v != 7 {
+		t.Fatal(v, ok)
+	}
+	if v, ok := First([]int{}); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+	if v, ok := Last([]int(nil)); ok || v != nil {
+		t.Fatal(v, ok)
+	}
+}
+
+func TestTake(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(Take(s, 0), []int{}) ||
+		!reflect.DeepEqual(Take(s, 2), []int{1, 2}) ||
+		!reflect.DeepEqual(Take(s, 10), []int{1, 2, 3, 4}) ||
+		!reflect.DeepEqual(Take(s, -1), []int{}) {
+		t.Fatal()
+	}
+
+	// The result does not alias the input.
+	r := Take(s, 2).([]int)
+	r = append(r, 100)
+	if s[2] != 3 {
+		t.Fatal()
+	}
+}
+
+func TestDrop(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(Drop(s, 0), []int{1, 2, 3, 4}) ||
+		!reflect.DeepEqual(Drop(s, 1), []int{2, 3, 4}) ||
+		!reflect.DeepEqual(Drop(s, 10), []int{}) ||
+		!reflect.DeepEqual(Drop(s, -1), []int{1, 2, 3, 4}) {
+		t.Fatal()
+	}
+}
+
+func TestTakeLast(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(TakeLast(s, 0), []string{}) ||
+		!reflect.DeepEqual(TakeLast(s, 2), []string{"b", "c"}) ||
+		!reflect.DeepEqual(TakeLast(s, 10), []string{"a", "b", "c"}) ||
+		!reflect.DeepEqual(TakeLast(s, -1), []string{}) {
+		t.Fatal()
+	}
+}
+
+func TestDropLast(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(DropLast(s, 0), []string{"a", "b", "c"}) ||
+		!reflect.DeepEqual(DropLast(s, 2), []string{"a"}) ||
+		!reflect.DeepEqual(DropLast(s, 10), []string{}) ||
+		!reflect.DeepEqual(DropLast(s, -1), []string{"a", "b", "c"}) {
+		t.Fatal()
+	}
+}
+
+func TestTakeWhile(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(TakeWhile(s, func(i int) bool { return i < 3 }), []int{1, 2}) ||
+		!reflect.DeepEqual(TakeWhile(s, func(i int) bool { return i > 3 }), []int{}) ||
+		!reflect.DeepEqual(TakeWhile(s, func(i int) bool { return i > 0 }), s) {
+		t.Fatal()
+	}
+}
+
+func TestDropWhile(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5}
+	if !reflect.DeepEqual(DropWhile(s, func(i int) bool { return i < 3 }), []int{3, 4, 5}) ||
+		!reflect.DeepEqual(DropWhile(s, func(i int) bool { return i > 3 }), s) ||
+		!reflect.DeepEqual(DropWhile(s, func(i int) bool { return i > 0 }), []int{}) {
+		t.Fatal()
+	}
+}
+
+func TestRotateInPlaceReflect(t *testing.T) {
+	cases := []struct {
+		k    int
+		want []int
+	}{
+		{1, []int{2, 3, 4, 5, 1}},
+		{-1, []int{5, 1, 2, 3, 4}},
+		{0, []int{1, 2, 3, 4, 5}},
+		{5, []int{1, 2, 3, 4, 5}},
+		{7, []int{3, 4, 5, 1, 2}},
+		{-12, []int{4, 5, 1, 2, 3}},
+	}
+	for _, c := range cases {
+		s := []int{1, 2, 3, 4, 5}
+		RotateInPlace(s, c.k)
+		if !reflect.DeepEqual(s, c.want) {
+			t.Fatal(c.k, s)
+		}
+	}
+
+	RotateInPlace([]int{}, 3)
+}
+
+func TestRotated(t *testing.T) {
+	s := []string{"a", "b", "c"}
+	if r := Rotated(s, 1); !reflect.DeepEqual(r, []string{"b", "c", "a"}) {
+		t.Fatal(r)
+	}
+	if r := Rotated(s, -4); !reflect.DeepEqual(r, []string{"c", "a", "b"}) {
+		t.Fatal(r)
+	}
+	if !reflect.DeepEqual(s, []string{"a", "b", "c"}) {
+		t.Fatal(s)
+	}
+	if r := Rotated([]int{}, 1); !reflect.DeepEqual(r, []int{}) {
+		t.Fatal(r)
+	}
+}
+
+func TestSplitAt(t *testing.T) {
+	s := []int{1, 2, 3}
+	cases := []struct {
+		index       int
+		left, right []interface{}
+	}{
+		{1, []interface{}{1}, []interface{}{2, 3}},
+		{0, []interface{}{}, []interface{}{1, 2, 3}},
+		{3, []interface{}{1, 2, 3}, []interface{}{}},
+		{-2, []interface{}{}, []interface{}{1, 2, 3}},
+		{10, []interface{}{1, 2, 3}, []interface{}{}},
+	}
+	for _, c := range cases {
+		left, right := SplitAt(s, c.index)
+		if !reflect.DeepEqual(left, c.left) || !reflect.DeepEqual(right, c.right) {
+			t.Fatal(c.index, left, right)
+		}
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	if !reflect.DeepEqual(Rotate(s, 1), []interface{}{2, 3, 4, 1}) ||
+		!reflect.DeepEqual(Rotate(s, -1), []interface{}{4, 1, 2, 3}) ||
+		!reflect.DeepEqual(Rotate(s, 9), []interface{}{2, 3, 4, 1}) ||
+		!reflect.DeepEqual(Rotate(s, 0), []interface{}{1, 2, 3, 4}) ||
+		!reflect.DeepEqual(Rotate([]int{}, 3), []interface{}{}) {
+		t.Fatal()
+	}
+}
+
+func TestShuffle(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	Shuffle(s)
+	sort.Ints(s)
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Fatal()
+	}
+}
+
+func TestShuffleWithRand(t *testing.T) {
+	s1 := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	s2 := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	ShuffleWithRand(s1, rand.New(rand.NewSource(1)))
+	ShuffleWithRand(&s2, rand.New(rand.NewSource(1)))
+
+	expected := []int{6, 1, 8, 2, 3, 4, 7, 5}
+	if !reflect.DeepEqual(s1, expected) || !reflect.DeepEqual(s2, expected) {
+		t.Fatal()
+	}
+}
+
+func TestSample(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	r1 := Sample(s, 3, rand.New(rand.NewSource(1))).([]int)
+	r2 := Sample(s, 3, rand.New(rand.NewSource(1))).([]int)
+	if len(r1) != 3 || !reflect.DeepEqual(r1, r2) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3, 4, 5, 6, 7, 8}) {
+		t.Fatal()
+	}
+
+	r3 := Sample(s, 10, nil).([]int)
+	sort.Ints(r3)
+	if !reflect.DeepEqual(r3, s) {
+		t.Fatal()
+	}
+
+	if len(Sample(s, -1, nil).([]int)) != 0 || len(Sample([]int{}, 2, nil).([]int)) != 0 {
+		t.Fatal()
+	}
+}
+
+func TestSampleNoDuplicates(t *testing.T) {
+	s := []int{1, 2, 3, 4, 5, 6, 7, 8}
+	r := rand.New(rand.NewSource(42))
+	for i := 0; i < 100; i++ {
+		seen := map[int]bool{}
+		for _, v := range Sample(s, 5, r).([]int) {
+			if seen[v] {
+				t.Fatal()
+			}
+			seen[v] = true
+		}
+	}
+}
+
+func TestSampleOne(t *testing.T) {
+	v1, ok1 := SampleOne([]string{"a", "b"}, rand.New(rand.NewSource(1)))
+	v2, ok2 := SampleOne([]string{"a", "b"}, rand.New(rand.NewSource(1)))
+	_, ok3 := SampleOne([]string{}, nil)
+	if !ok1 || !ok2 || v1 != v2 || ok3 {
+		t.Fatal()
+	}
+}
+
+func TestInsert(t *testing.T) {
+	s := []int{1, 2, 3}
+	if !reflect.DeepEqual(Insert(s, 0, 10, 20), []int{10, 20, 1, 2, 3}) ||
+		!reflect.DeepEqual(Insert(s, 1, 10), []int{1, 10, 2, 3}) ||
+		!reflect.DeepEqual(Insert(s, 3, 10), []int{1, 2, 3, 10}) ||
+		!reflect.DeepEqual(Insert(s, 1), []int{1, 2, 3}) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3}) {
+		t.Fatal()
+	}
+}
+
+func TestInsertPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice: value type string is not assignable to element type int." {
+			t.Fatal(r)
+		}
+	}()
+	Insert([]int{1, 2, 3}, 1, "2")
+}
+
+func TestInsertOutOfRange(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal()
+		}
+	}()
+	Insert([]int{1, 2, 3}, 4, 4)
+}
+
+func TestRemoveAt(t *testing.T) {
+	s := []int{1, 2, 3}
+	if !reflect.DeepEqual(RemoveAt(s, 0), []int{2, 3}) ||
+		!reflect.DeepEqual(RemoveAt(s, 1), []int{1, 3}) ||
+		!reflect.DeepEqual(RemoveAt(s, 2), []int{1, 2}) ||
+		!reflect.DeepEqual(RemoveAt([]string{"a"}, 0), []string{}) {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(s, []int{1, 2, 3}) {
+		t.Fatal()
+	}
+}
+
+func TestFill(t *testing.T) {
+	s := []int{1, 2, 3}
+	Fill(s, 7)
+	if !reflect.DeepEqual(s, []int{7, 7, 7}) {
+		t.Fatal(s)
+	}
+	Fill(&s, 8)
+	if !reflect.DeepEqual(s, []int{8, 8, 8}) {
+		t.Fatal(s)
+	}
+
+	ptrs := []*foo{{"a"}, {"b"}}
+	Fill(ptrs, nil)
+	if ptrs[0] != nil || ptrs[1] != nil {
+		t.Fatal(ptrs)
+	}
+	Fill([]int{}, 1)
+}
+
+func TestFillRange(t *testing.T) {
+	s := []int{1, 2, 3, 4}
+	FillRange(s, 1, 3, 0)
+	if !reflect.DeepEqual(s, []int{1, 0, 0, 4}) {
+		t.Fatal(s)
+	}
+	FillRange(s, 0, 4, 5)
+	FillRange(s, 4, 4, 9)
+	FillRange(s, 0, 0, 9)
+	if !reflect.DeepEqual(s, []int{5, 5, 5, 5}) {
+		t.Fatal(s)
+	}
+}
+
+func TestFillPanic(t *testing.T) {
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/slice: range [2, 5) out of range [0, 4]." {
+				t.Fatal(r)
+			}
+		}()
+		FillRange([]int{1, 2, 3, 4}, 2, 5, 0)
+	}()
+
+	defer func() {
+		r := recover()
+		if r != "utils/slice: value type string is not assignable to element type int." {
+			t.Fatal(r)
+		}
+	}()
+	Fill([]int{1}, "a")
+}
+
+func TestJoin(t *testing.T) {
+	if r := Join([]int{1, 2, 3}, ", "); r != "1, 2, 3" {
+		t.Fatal(r)
+	}
+	if r := Join([]string{"a"}, ", "); r != "a" {
+		t.Fatal(r)
+	}
+	if r := Join([]int{}, ", "); r != "" {
+		t.Fatal(r)
+	}
+}
+
+func TestJoinFunc(t *testing.T) {
+	users := []user{{"a", "x@a.com"}, {"b", "y@a.com"}}
+	r := JoinFunc(users, "; ", func(u user) string { return u.name + " <" + u.email + ">" })
+	if r != "a <x@a.com>; b <y@a.com>" {
+		t.Fatal(r)
+	}
+	if r := JoinFunc(users[:1], "; ", func(u user) string { return u.name }); r != "a" {
+		t.Fatal(r)
+	}
+	if r := JoinFunc([]user{}, "; ", func(u user) string { return u.name }); r != "" {
+		t.Fatal(r)
+	}
+}
+
+func TestConcat(t *testing.T) {
+	r := Concat([]string{"a", "b"}, []string{}, &[]string{"c"})
+	if !reflect.DeepEqual(r, []string{"a", "b", "c"}) {
+		t.Fatal()
+	}
+
+	r = Concat([]interface{}{1}, []int{2, 3})
+	if !reflect.DeepEqual(r, []interface{}{1, 2, 3}) {
+		t.Fatal()
+	}
+
+	if Concat() != nil {
+		t.Fatal()
+	}
+}
+
+func TestConcatPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice: argument 1 element type int is not assignable to string." {
+			t.Fatal(r)
+		}
+	}()
+	Concat([]string{"a"}, []int{1})
+}
+
+func TestInterleave(t *testing.T) {
+	r := Interleave([]int{1, 2, 3}, []int{10}, []int{20, 30})
+	if !reflect.DeepEqual(r, []int{1, 10, 20, 2, 30, 3}) {
+		t.Fatal(r)
+	}
+	if r := Interleave([]string{}, []string{"a", "b"}); !reflect.DeepEqual(r, []string{"a", "b"}) {
+		t.Fatal(r)
+	}
+	if Interleave() != nil {
+		t.Fatal()
+	}
+
+	// One slice results in a copy.
+	s := []int{1, 2}
+	c := Interleave(s).([]int)
+	c[0] = 100
+	if !reflect.DeepEqual(c, []int{100, 2}) || s[0] != 1 {
+		t.Fatal(c, s)
+	}
+}
+
+func TestInterleavePanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice: argument 1 element type string is not assignable to int." {
+			t.Fatal(r)
+		}
+	}()
+	Interleave([]int{1}, []string{"a"})
+}
+
+func TestZipWith(t *testing.T) {
+	add := func(a, b int) int { return a + b }
+	if r := ZipWith([]int{1, 2}, []int{10, 20}, add); !reflect.DeepEqual(r, []interface{}{11, 22}) {
+		t.Fatal(r)
+	}
+	if r := ZipWith([]int{1, 2, 3}, []int{10}, add); !reflect.DeepEqual(r, []interface{}{11}) {
+		t.Fatal(r)
+	}
+	if r := ZipWith([]int{}, []int{10}, add); len(r) != 0 {
+		t.Fatal(r)
+	}
+
+	r := ZipWith([]string{"a", "b"}, []int{1, 2}, func(s string, i int) string {
+		return fmt.Sprint(s, i)
+	})
+	if !reflect.DeepEqual(r, []interface{}{"a1", "b2"}) {
+		t.Fatal(r)
+	}
+}
+
+func TestZipWithPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.ZipWith: combiner must be func(string, int) R, got func(int, int) int." {
+			t.Fatal(r)
+		}
+	}()
+	ZipWith([]string{"a"}, []int{1}, func(a, b int) int { return a + b })
+}
+
+type user struct {
+	name  string
+	email string
+}
+
+func TestUniqBy(t *testing.T) {
+	users := []user{{"a", "x@a.com"}, {"b", "y@a.com"}, {"c", "x@a.com"}, {"d", "y@a.com"}, {"e", "z@a.com"}}
+	r := UniqBy(users, func(u user) string { return u.email })
+	if !reflect.DeepEqual(r, []interface{}{users[0], users[1], users[4]}) {
+		t.Fatal(r)
+	}
+
+	// Non-comparable keys.
+	r = UniqBy([]int{1, 2, 3, 4}, func(i int) []int { return []int{i % 2} })
+	if !reflect.DeepEqual(r, []interface{}{1, 2}) {
+		t.Fatal(r)
+	}
+
+	if r := UniqBy([]int{}, func(i int) int { return i }); len(r) != 0 {
+		t.Fatal(r)
+	}
+}
+
+func TestEqual(t *testing.T) {
+	if !Equal([]int{1, 2}, []int{1, 2}) || Equal([]int{1, 2}, []int{2, 1}) ||
+		Equal([]int{1, 2}, []int{1, 2, 3}) || Equal([]int{1}, []int64{1}) {
+		t.Fatal()
+	}
+	if !Equal([][]int{{1}, {2}}, [][]int{{1}, {2}}) || Equal([][]int{{1}}, [][]int{{2}}) {
+		t.Fatal()
+	}
+
+	// nil and empty are equal.
+	if !Equal([]int(nil), []int{}) || !Equal([]string{}, []int(nil)) {
+		t.Fatal()
+	}
+
+	// Comparable types holding non-comparable values.
+	if !Equal([]box{{[]int{1}}}, []box{{[]int{1}}}) || Equal([]box{{[]int{1}}}, []box{{[]int{2}}}) ||
+		!Equal([]interface{}{[2]interface{}{map[int]int{}, 1}}, []interface{}{[2]interface{}{map[int]int{}, 1}}) {
+		t.Fatal()
+	}
+	if CountValue([]box{{[]int{1}}, {1}, {[]int{1}}}, box{[]int{1}}) != 2 {
+		t.Fatal()
+	}
+}
+
+func TestEqualFunc(t *testing.T) {
+	users := []user{{"a", "x@a.com"}, {"b", "y@a.com"}}
+	sameName := func(u user, name string) bool { return u.name == name }
+	if !EqualFunc(users, []string{"a", "b"}, sameName) ||
+		EqualFunc(users, []string{"a", "c"}, sameName) ||
+		EqualFunc(users, []string{"a"}, sameName) {
+		t.Fatal()
+	}
+	if !EqualFunc([]user(nil), []string{}, sameName) {
+		t.Fatal()
+	}
+}
+
+func TestEqualFuncPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.EqualFunc: eq must be func(int, string) bool, got func(int, int) bool." {
+			t.Fatal(r)
+		}
+	}()
+	EqualFunc([]int{1}, []string{"1"}, func(a, b int) bool { return a == b })
+}
+
+func TestEqualUnordered(t *testing.T) {
+	if !EqualUnordered([]int{1, 2, 2, 3}, []int{2, 3, 1, 2}) || EqualUnordered([]int{1, 2, 2}, []int{1, 1, 2}) ||
+		EqualUnordered([]int{1, 2}, []int{1, 2, 3}) || EqualUnordered([]int{1}, []int64{1}) {
+		t.Fatal()
+	}
+	if !EqualUnordered([][]int{{1}, {2}, {1}}, [][]int{{2}, {1}, {1}}) ||
+		EqualUnordered([][]int{{1}, {1}}, [][]int{{1}, {2}}) {
+		t.Fatal()
+	}
+	if !EqualUnordered([]interface{}{1, []int{1}}, []interface{}{[]int{1}, 1}) {
+		t.Fatal()
+	}
+	if !EqualUnordered([]box{{[]int{1}}, {2}, {[]int{1}}}, []box{{2}, {[]int{1}}, {[]int{1}}}) ||
+		EqualUnordered([]box{{[]int{1}}, {[]int{1}}}, []box{{[]int{1}}, {[]int{2}}}) {
+		t.Fatal()
+	}
+
+	if !EqualUnordered([]int(nil), []string{}) {
+		t.Fatal()
+	}
+}
+
+func TestDifference(t *testing.T) {
+	if !reflect.DeepEqual(Difference([]int{1, 2, 2, 3, 4}, []int{3, 1}), []int{2, 2, 4}) ||
+		!reflect.DeepEqual(Difference([]string{"a", "b"}, []string{}), []string{"a", "b"}) ||
+		!reflect.DeepEqual(Difference([]string{"a", "b"}, []string{"b", "a"}), []string{}) {
+		t.Fatal()
+	}
+
+	r := Difference([][]int{{1}, {2}}, [][]int{{2}})
+	if !reflect.DeepEqual(r, [][]int{{1}}) {
+		t.Fatal()
+	}
+}
+
+func TestIntersection(t *testing.T) {
+	if !reflect.DeepEqual(Intersection([]int{1, 2, 2, 3, 4}, []int{4, 2, 5}), []int{2, 2, 4}) ||
+		!reflect.DeepEqual(Intersection([]string{"a", "b"}, []string{"c"}), []string{}) {
+		t.Fatal()
+	}
+}
+
+func TestUnion(t *testing.T) {
+	if !reflect.DeepEqual(Union([]int{1, 2, 2, 3}, []int{4, 3, 1, 5}), []int{1, 2, 3, 4, 5}) ||
+		!reflect.DeepEqual(Union([]string{}, []string{"a", "a"}), []string{"a"}) {
+		t.Fatal()
+	}
+}
+
+// A comparable struct type holding a non-comparable value.
+type box struct {
+	X interface{}
+}
+
+func TestSetOperationsUncomparableValues(t *testing.T) {
+	a := []box{{[]int{1}}, {2}, {[]int{3}}}
+	b := []box{{[]int{3}}, {4}}
+
+	if r := Difference(a, b); !reflect.DeepEqual(r, []box{{[]int{1}}, {2}}) {
+		t.Fatal(r)
+	}
+	if r := Intersection(a, b); !reflect.DeepEqual(r, []box{{[]int{3}}}) {
+		t.Fatal(r)
+	}
+	if r := Union(a, b); !reflect.DeepEqual(r, []box{{[]int{1}}, {2}, {[]int{3}}, {4}}) {
+		t.Fatal(r)
+	}
+	if r := UniqBy([]int{1, 2, 3}, func(i int) box { return box{[]int{i % 2}} }); !reflect.DeepEqual(r, []interface{}{1, 2}) {
+		t.Fatal(r)
+	}
+	if r := Frequencies([]box{{[]int{1}}, {[]int{1}}}); len(r) != 1 || r[0].Count != 2 {
+		t.Fatal(r)
+	}
+}
+
+type version struct {
+	major int
+	name  string
+}
+
+func TestBinarySearch(t *testing.T) {
+	s := []int{1, 3, 5, 5, 5, 7}
+	cmp := func(e, target int) int { return e - target }
+	for _, c := range []struct {
+		target, index int
+		ok            bool
+	}{{5, 2, true}, {1, 0, true}, {7, 5, true}, {4, 2, false}, {0, 0, false}, {8, 6, false}} {
+		if i, ok := BinarySearch(s, c.target, cmp); i != c.index || ok != c.ok {
+			t.Fatal(c.target, i, ok)
+		}
+	}
+	if i, ok := BinarySearch([]int(nil), 1, cmp); i != 0 || ok {
+		t.Fatal()
+	}
+
+	// The target may have a different type than the elements.
+	versions := []version{{1, "a"}, {2, "b"}, {4, "c"}}
+	byMajor := func(v version, major int) int { return v.major - major }
+	if i, ok := BinarySearch(&versions, 4, byMajor); i != 2 || !ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearch(versions, 3, byMajor); i != 2 || ok {
+		t.Fatal()
+	}
+
+	// cmp may return any signed integer type.
+	type ordering int8
+	byName := func(v version, name string) ordering { return ordering(strings.Compare(v.name, name)) }
+	if i, ok := BinarySearch(versions, "b", byName); i != 1 || !ok {
+		t.Fatal()
+	}
+}
+
+func TestBinarySearchPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.BinarySearch: cmp must be func(int, A) int, got func(string, int) int." {
+			t.Fatal(r)
+		}
+	}()
+	BinarySearch([]int{1}, 1, func(e string, target int) int { return 0 })
+}
+
+func TestBinarySearchBoolPanic(t *testing.T) {
+	defer func() {
+		r := recover()
+		if r != "utils/slice.BinarySearch: cmp must be func(int, A) int, got func(int, int) bool." {
+			t.Fatal(r)
+		}
+	}()
+	BinarySearch([]int{1}, 1, func(a, b int) bool { return a < b })
+}
+
+func TestBinarySearchLess(t *testing.T) {
+	s := []int{1, 3, 5, 5, 7}
+	less := func(a, b int) bool { return a < b }
+
+	i1, ok1 := BinarySearchLess(s, 5, less)
+	i2, ok2 := BinarySearchLess(s, 4, less)
+	i3, ok3 := BinarySearchLess(s, 0, less)
+	i4, ok4 := BinarySearchLess(s, 8, less)
+	i5, ok5 := BinarySearchLess([]int{}, 1, less)
+
+	if i1 != 2 || !ok1 || i2 != 2 || ok2 {
+		t.Fatal()
+	}
+	if i3 != 0 || ok3 || i4 != 5 || ok4 || i5 != 0 || ok5 {
+		t.Fatal()
+	}
+
+	i6, ok6 := BinarySearchLess(&[]string{"a", "b", "c"}, "c",
+		func(a, b string) bool { return a < b })
+	if i6 != 2 || !ok6 {
+		t.Fatal()
+	}
+}
+
+func TestBinarySearchIntsStrings(t *testing.T) {
+	ints := []int{1, 3, 3, 5}
+	if i, ok := BinarySearchInts(ints, 3); i != 1 || !ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearchInts(ints, 0); i != 0 || ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearchInts(ints, 6); i != 4 || ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearchInts(nil, 1); i != 0 || ok {
+		t.Fatal()
+	}
+
+	strs := []string{"a", "c", "c", "e"}
+	if i, ok := BinarySearchStrings(strs, "c"); i != 1 || !ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearchStrings(strs, "b"); i != 1 || ok {
+		t.Fatal()
+	}
+	if i, ok := BinarySearchStrings(strs, "f"); i != 4 || ok {
+		t.Fatal()
+	}
+}
+
+func TestArray(t *testing.T) {
+	a := [5]int{1, 2, 3, 4, 6}
+	isMultipleOf3 := func(i int) bool { return i%3 == 0 }
+
+	sum := 0
+	Foreach(a, func(i int) { sum += i })
+	if sum != 16 {
+		t.Fatal()
+	}
+
+	if !reflect.DeepEqual(Map(&a, func(i int) int { return i * 10 }), []interface{}{10, 20, 30, 40, 60}) ||
+		!reflect.DeepEqual(Filter(a, isMultipleOf3), []interface{}{3, 6}) ||
+		!Exist(&a, isMultipleOf3) ||
+		Index(a, isMultipleOf3) != 2 ||
+		IndexLast(&a, isMultipleOf3) != 4 {
+		t.Fatal()
+	}
+	if ok, r := Find(a, isMultipleOf3); !ok || r != 3 {
+		t.Fatal()
+	}
+	if ok, r := FindLast(&a, isMultipleOf3); !ok || r != 6 {
+		t.Fatal()
+	}
+	if !reflect.DeepEqual(Take(a, 2), []int{1, 2}) {
+		t.Fatal()
+	}
+}
+
+func TestArrayInPlace(t *testing.T) {
+	a := [3]int{1, 2, 3}
+	Fill(&a, 0)
+	FillRange(&a, 1, 2, 5)
+	if a != [3]int{0, 5, 0} {
+		t.Fatal(a)
+	}
+	Apply(&a, func(i int) int { return i + 1 })
+	RotateInPlace(&a, 1)
+	if a != [3]int{6, 1, 1} {
+		t.Fatal(a)
+	}
+
+	const msg = "utils/slice: array must be passed by pointer, [3]int."
+	expectPanic(t, msg, func() { Fill(a, 0) })
+	expectPanic(t, msg, func() { FillRange(a, 0, 1, 0) })
+	expectPanic(t, msg, func() { Apply(a, func(i int) int { return i }) })
+	expectPanic(t, msg, func() { RotateInPlace(a, 1) })
+	expectPanic(t, msg, func() { Shuffle(a) })
+	expectPanic(t, msg, func() { ShuffleWithRand(a, rand.New(rand.NewSource(1))) })
+	expectPanic(t, "utils/slice: argument type is not slice pointer, [3]int.", func() {
+		RemoveIf(a, func(i int) bool { return true })
+	})
+}
+
+func TestArrayNoPanic(t *testing.T) {
+	err := Safe(func() {
+		Foreach([5]int{}, func(i int) {})
+		Foreach(&[5]int{}, func(i int) {})
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
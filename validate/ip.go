@@ -0,0 +1,86 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"net"
+	"strings"
+
+	"github.com/uestcer/utils/errors"
+)
+
+// Check if s is an IPv4 address in dotted decimal form, e.g. "192.0.2.1".
+// IPv4-mapped IPv6 addresses like "::ffff:192.0.2.1" are IPv6.
+func IsIPv4(s string) bool {
+	return net.ParseIP(s) != nil && !strings.Contains(s, ":")
+}
+
+// Check if s is an IPv6 address, e.g. "2001:db8::1".
+func IsIPv6(s string) bool {
+	return net.ParseIP(s) != nil && strings.Contains(s, ":")
+}
+
+// Check if s is an IPv4 or IPv6 address.
+func IsIP(s string) bool {
+	return net.ParseIP(s) != nil
+}
+
+// Check if s is an IP address and prefix length in CIDR notation,
+// e.g. "192.0.2.0/24" or "2001:db8::/32".
+func IsCIDR(s string) bool {
+	_, _, err := net.ParseCIDR(s)
+	return err == nil
+}
+
+// Check if the address ip is in the network cidr, e.g.
+// IPInRange("192.0.2.1", "192.0.2.0/24") returns true.
+// An IPv4 address is in an IPv4-mapped IPv6 network, and vice versa.
+// Return an error if ip is not an IP address, or cidr is not in CIDR notation.
+func IPInRange(ip, cidr string) (bool, error) {
+	addr := net.ParseIP(ip)
+	if addr == nil {
+		return false, errors.Newf("utils/validate: %q is not an IP address", ip)
+	}
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return false, errors.Newf("utils/validate: %q is not a CIDR", cidr)
+	}
+	return network.Contains(addr), nil
+}
+
+// A rule failing if v is not a string of an IPv4 address, see IsIPv4.
+func IPv4() Rule {
+	return stringRule(IsIPv4, "an IPv4 address")
+}
+
+// A rule failing if v is not a string of an IPv6 address, see IsIPv6.
+func IPv6() Rule {
+	return stringRule(IsIPv6, "an IPv6 address")
+}
+
+// A rule failing if v is not a string of an IP address, see IsIP.
+func IP() Rule {
+	return stringRule(IsIP, "an IP address")
+}
+
+// A rule failing if v is not a string in CIDR notation, see IsCIDR.
+func CIDR() Rule {
+	return stringRule(IsCIDR, "a CIDR")
+}
+
+// A rule failing if v is not a string passing check, what describes the
+// valid strings in the error message.
+func stringRule(check func(string) bool, what string) Rule {
+	return func(v interface{}) error {
+		s, err := str(v)
+		if err != nil {
+			return err
+		}
+		if !check(s) {
+			return errors.Newf("utils/validate: %q is not %s", s, what)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/uestcer/utils/errors"
+)
+
+func TestIsIP(t *testing.T) {
+	for _, s := range []string{"192.0.2.1", "0.0.0.0", "255.255.255.255"} {
+		if !IsIPv4(s) || IsIPv6(s) || !IsIP(s) {
+			t.Fatal(s)
+		}
+	}
+	for _, s := range []string{"2001:db8::1", "::1", "::", "::ffff:192.0.2.1", "FE80::1"} {
+		if IsIPv4(s) || !IsIPv6(s) || !IsIP(s) {
+			t.Fatal(s)
+		}
+	}
+	for _, s := range []string{"", "256.0.0.1", "1.2.3", "1.2.3.4.5", "1.2.3.4/24", "2001:db8:::1", "fe80::1%eth0", "a.b.c.d", " 1.2.3.4"} {
+		if IsIPv4(s) || IsIPv6(s) || IsIP(s) {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestIsCIDR(t *testing.T) {
+	for _, s := range []string{"192.0.2.0/24", "192.0.2.1/32", "0.0.0.0/0", "2001:db8::/32"} {
+		if !IsCIDR(s) {
+			t.Fatal(s)
+		}
+	}
+	for _, s := range []string{"", "192.0.2.0", "192.0.2.0/33", "192.0.2.0/", "2001:db8::/129", "/24"} {
+		if IsCIDR(s) {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestIPInRange(t *testing.T) {
+	cases := []struct {
+		ip, cidr string
+		in       bool
+	}{
+		{"192.0.2.1", "192.0.2.0/24", true},
+		{"192.0.3.1", "192.0.2.0/24", false},
+		{"10.1.2.3", "0.0.0.0/0", true},
+		{"2001:db8::1", "2001:db8::/32", true},
+		{"2001:db9::1", "2001:db8::/32", false},
+		{"192.0.2.1", "2001:db8::/32", false},
+		{"::ffff:192.0.2.1", "192.0.2.0/24", true},
+	}
+	for _, c := range cases {
+		if in, err := IPInRange(c.ip, c.cidr); in != c.in || err != nil {
+			t.Fatal(c.ip, c.cidr, err)
+		}
+	}
+
+	if _, err := IPInRange("x", "192.0.2.0/24"); errors.Message(err) != `utils/validate: "x" is not an IP address` {
+		t.Fatal(err)
+	}
+	if _, err := IPInRange("192.0.2.1", "192.0.2.0"); errors.Message(err) != `utils/validate: "192.0.2.0" is not a CIDR` {
+		t.Fatal(err)
+	}
+}
+
+func TestIPRules(t *testing.T) {
+	v4, v6 := "192.0.2.1", "2001:db8::1"
+	if IPv4()(v4) != nil || IPv4()(v6) == nil || IPv6()(v6) != nil || IPv6()(&v4) == nil {
+		t.Fatal()
+	}
+	if IP()(v4) != nil || IP()(&v6) != nil || IP()(1) == nil {
+		t.Fatal()
+	}
+	if CIDR()("192.0.2.0/24") != nil {
+		t.Fatal()
+	}
+	if err := CIDR()(v4); errors.Message(err) != `utils/validate: "192.0.2.1" is not a CIDR` {
+		t.Fatal(err)
+	}
+	if err := IPv6()(v4); errors.Message(err) != `utils/validate: "192.0.2.1" is not an IPv6 address` {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,197 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/uestcer/utils/errors"
+)
+
+// Validate the exported fields of struct v by their "validate" tag, and
+// return one error per failed field, whose message has the field path,
+// e.g. "utils/validate: Address.City: value is required".
+// The tag is a comma separated list of rules, checked in order until the
+// first failure:
+//
+//	required   see Required
+//	min=N      see MinLength for strings, slices, arrays and maps, otherwise Min
+//	max=N      see MaxLength for strings, slices, arrays and maps, otherwise Max
+//	email      see Email
+//	url        see URL
+//	regex=P    see Matches, P is the rest of the tag, so it may contain commas
+//
+// Fields without required are optional, their rules are skipped if they are
+// nil or have zero length, e.g. an empty string, a zero number is checked.
+// Nested structs and non-nil struct pointers are validated recursively, a
+// struct reached by several pointers, e.g. in a cycle, is validated once.
+// Example: Name string `validate:"required,min=2,max=50"`
+// NOTE: Panic if v is not a struct or struct pointer, or a tag is invalid.
+func ValidateStruct(v interface{}) []error {
+	s := &structValidator{visited: make(map[visitKey]bool)}
+	rv := s.indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("utils/validate: argument type is not struct, %T.", v))
+	}
+
+	s.validate(rv, "")
+	return s.errs
+}
+
+// The parsed tag of a struct field.
+type fieldRules struct {
+	index    int
+	name     string
+	rule     Rule
+	required bool
+}
+
+// The parsed tags of struct types, by reflect.Type.
+var structRules sync.Map
+
+// Identify a struct pointer already validated.
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+type structValidator struct {
+	visited map[visitKey]bool
+	errs    []error
+}
+
+func (s *structValidator) validate(v reflect.Value, prefix string) {
+	for _, f := range rulesOf(v.Type()) {
+		path := prefix + f.name
+		fv := v.Field(f.index)
+
+		if f.rule != nil && (f.required || !absent(fv)) {
+			if err := f.rule(fv.Interface()); err != nil {
+				msg := strings.TrimPrefix(errors.Message(err), "utils/validate: ")
+				s.errs = append(s.errs, errors.Newf("utils/validate: %s: %s", path, msg))
+				continue
+			}
+		}
+
+		if fv = s.indirect(fv); fv.Kind() == reflect.Struct {
+			s.validate(fv, path+".")
+		}
+	}
+}
+
+// Check if the optional field v is absent, i.e. nil or of zero length.
+func absent(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+	return hasLen(v) && v.Len() == 0
+}
+
+// Dereference the pointers of v, return an invalid Value for a nil pointer
+// or a pointer already visited.
+func (s *structValidator) indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		key := visitKey{v.Pointer(), v.Type()}
+		if v.IsNil() || s.visited[key] {
+			return reflect.Value{}
+		}
+		s.visited[key] = true
+		v = v.Elem()
+	}
+	return v
+}
+
+// Return the parsed tags of the exported fields of struct type t.
+// NOTE: Panic if a tag is invalid.
+func rulesOf(t reflect.Type) []fieldRules {
+	if rules, ok := structRules.Load(t); ok {
+		return rules.([]fieldRules)
+	}
+
+	var rules []fieldRules
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		f := fieldRules{index: i, name: field.Name}
+		if tag := field.Tag.Get("validate"); tag != "" {
+			var rs []Rule
+			rs, f.required = tagRules(tag, field.Name, field.Type)
+			f.rule = All(rs...)
+		}
+		rules = append(rules, f)
+	}
+	structRules.Store(t, rules)
+	return rules
+}
+
+// Parse the rules of tag for a field of type t, and whether it is required.
+// NOTE: Panic if tag is invalid.
+func tagRules(tag, field string, t reflect.Type) ([]Rule, bool) {
+	var rules []Rule
+	required := false
+	for tag != "" {
+		var part string
+		if strings.HasPrefix(tag, "regex=") {
+			part, tag = tag, ""
+		} else if i := strings.Index(tag, ","); i != -1 {
+			part, tag = tag[:i], tag[i+1:]
+		} else {
+			part, tag = tag, ""
+		}
+
+		name, arg, _ := strings.Cut(strings.TrimSpace(part), "=")
+		switch name {
+		case "required":
+			required = true
+			rules = append(rules, Required())
+		case "min", "max":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				panic(fmt.Sprintf("utils/validate: invalid tag %q of field %s.", part, field))
+			}
+			rules = append(rules, boundRule(name, n, t))
+		case "email":
+			rules = append(rules, Email())
+		case "url":
+			rules = append(rules, URL())
+		case "regex":
+			re, err := regexp.Compile(arg)
+			if err != nil {
+				panic(fmt.Sprintf("utils/validate: invalid tag %q of field %s.", part, field))
+			}
+			rules = append(rules, matches(re))
+		default:
+			panic(fmt.Sprintf("utils/validate: invalid tag %q of field %s.", part, field))
+		}
+	}
+	return rules, required
+}
+
+// The length rule for types with a length, otherwise the number rule.
+func boundRule(name string, n float64, t reflect.Type) Rule {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	lengthy := hasLen(reflect.Zero(t))
+	switch {
+	case name == "min" && lengthy:
+		return MinLength(int(n))
+	case name == "max" && lengthy:
+		return MaxLength(int(n))
+	case name == "min":
+		return Min(n)
+	}
+	return Max(n)
+}
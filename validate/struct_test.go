@@ -0,0 +1,160 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/uestcer/utils/errors"
+)
+
+type address struct {
+	City string `validate:"required"`
+	Zip  string `validate:"regex=^[0-9]{5}(,[0-9]{4})?$"`
+}
+
+type account struct {
+	Name     string   `validate:"required,min=2,max=5"`
+	Email    string   `validate:"email"`
+	Homepage string   `validate:"url"`
+	Age      int      `validate:"min=18,max=150"`
+	Tags     []string `validate:"max=2"`
+	Address  address
+	Billing  *address
+	internal string `validate:"required"`
+	Note     string
+}
+
+func messages(errs []error) []string {
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = errors.Message(err)
+	}
+	return msgs
+}
+
+func TestValidateStruct(t *testing.T) {
+	valid := account{
+		Name:     "gophy",
+		Email:    "a@b.com",
+		Homepage: "https://go.dev",
+		Age:      20,
+		Address:  address{"Paris", "75001"},
+	}
+	if errs := ValidateStruct(&valid); errs != nil {
+		t.Fatal(messages(errs))
+	}
+
+	invalid := account{
+		Name:     "g",
+		Email:    "nope",
+		Homepage: "https://go.dev",
+		Age:      12,
+		Tags:     []string{"a", "b", "c"},
+		Address:  address{"", "12345,6789"},
+		Billing:  &address{"Lyon", "x"},
+	}
+	want := []string{
+		"utils/validate: Name: length 1 is less than 2",
+		`utils/validate: Email: "nope" is not an email address`,
+		"utils/validate: Age: 12 is less than 18",
+		"utils/validate: Tags: length 3 is greater than 2",
+		"utils/validate: Address.City: value is required",
+		`utils/validate: Billing.Zip: "x" does not match "^[0-9]{5}(,[0-9]{4})?$"`,
+	}
+	got := messages(ValidateStruct(invalid))
+	if len(got) != len(want) {
+		t.Fatal(got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatal(got[i], want[i])
+		}
+	}
+}
+
+func TestValidateStructOptional(t *testing.T) {
+	var empty account
+	empty.Name, empty.Age, empty.Address.City = "gophy", 20, "Paris"
+	if errs := ValidateStruct(empty); errs != nil {
+		t.Fatal(messages(errs))
+	}
+
+	required := struct {
+		Email string `validate:"required,email"`
+		Age   *int   `validate:"required,min=18"`
+	}{}
+	got := messages(ValidateStruct(required))
+	if len(got) != 2 || got[0] != "utils/validate: Email: value is required" ||
+		got[1] != "utils/validate: Age: value is required" {
+		t.Fatal(got)
+	}
+
+	zero := struct {
+		Count int      `validate:"min=1"`
+		Max   int      `validate:"max=-1"`
+		Limit *float64 `validate:"min=1"`
+	}{}
+	got = messages(ValidateStruct(zero))
+	if len(got) != 2 || got[0] != "utils/validate: Count: 0 is less than 1" ||
+		got[1] != "utils/validate: Max: 0 is greater than -1" {
+		t.Fatal(got)
+	}
+}
+
+type node struct {
+	Name string `validate:"required"`
+	Next *node
+}
+
+func TestValidateStructCycle(t *testing.T) {
+	n := &node{}
+	n.Next = n
+	got := messages(ValidateStruct(n))
+	if len(got) != 1 || got[0] != "utils/validate: Name: value is required" {
+		t.Fatal(got)
+	}
+
+	a, b := &node{Name: "a"}, &node{}
+	a.Next, b.Next = b, a
+	got = messages(ValidateStruct(a))
+	if len(got) != 1 || got[0] != "utils/validate: Next.Name: value is required" {
+		t.Fatal(got)
+	}
+}
+
+func TestValidateStructPanic(t *testing.T) {
+	func() {
+		defer func() {
+			r := recover()
+			if r != "utils/validate: argument type is not struct, int." {
+				t.Fatal(r)
+			}
+		}()
+		ValidateStruct(1)
+	}()
+
+	func() {
+		defer func() {
+			r := recover()
+			if r != `utils/validate: invalid tag "min=x" of field Name.` {
+				t.Fatal(r)
+			}
+		}()
+		ValidateStruct(struct {
+			Name string `validate:"min=x"`
+		}{})
+	}()
+
+	defer func() {
+		r := recover()
+		if r != `utils/validate: invalid tag "regex=[a-" of field Code.` {
+			t.Fatal(r)
+		}
+	}()
+	ValidateStruct(struct {
+		Code string `validate:"regex=[a-"`
+	}{})
+}
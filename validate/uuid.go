@@ -0,0 +1,31 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"regexp"
+)
+
+// The hyphenated RFC 4122 format of versions 1 to 5, in either case.
+var uuidPattern = regexp.MustCompile(
+	`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[1-5][0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
+
+// Check if s is a hyphenated RFC 4122 UUID of version 1 to 5, in lower or
+// upper case, e.g. "6ba7b810-9dad-11d1-80b4-00c04fd430c8".
+// The forms without hyphens, with braces or with a "urn:uuid:" prefix, and
+// the nil UUID are not accepted.
+func IsUUID(s string) bool {
+	return uuidPattern.MatchString(s)
+}
+
+// Same as IsUUID, but only accept version 4, the random UUID.
+func IsUUIDv4(s string) bool {
+	return IsUUID(s) && s[14] == '4'
+}
+
+// A rule failing if v is not a string of a UUID, see IsUUID.
+func UUID() Rule {
+	return stringRule(IsUUID, "a UUID")
+}
@@ -0,0 +1,65 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/uestcer/utils/errors"
+)
+
+func TestIsUUID(t *testing.T) {
+	valid := map[string]byte{
+		"6ba7b810-9dad-11d1-80b4-00c04fd430c8": '1',
+		"000003e8-3bd1-21ee-9e00-325096b39f47": '2',
+		"6fa459ea-ee8a-3ca4-894e-db77e160355e": '3',
+		"f47ac10b-58cc-4372-a567-0e02b2c3d479": '4',
+		"F47AC10B-58CC-4372-A567-0E02B2C3D479": '4',
+		"886313e1-3b8a-5372-9b90-0c9aee199e5d": '5',
+	}
+	for s, version := range valid {
+		if !IsUUID(s) || IsUUIDv4(s) != (version == '4') {
+			t.Fatal(s)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"00000000-0000-0000-0000-000000000000",
+		"f47ac10b58cc4372a5670e02b2c3d479",
+		"{f47ac10b-58cc-4372-a567-0e02b2c3d479}",
+		"urn:uuid:f47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"f47ac10b-58cc-4372-a567-0e02b2c3d47",
+		"f47ac10b-58cc-4372-a567-0e02b2c3d4790",
+		"f47ac10b-58cc-4372-a567_0e02b2c3d479",
+		"g47ac10b-58cc-4372-a567-0e02b2c3d479",
+		"f47ac10b-58cc-6372-a567-0e02b2c3d479",
+		"f47ac10b-58cc-0372-a567-0e02b2c3d479",
+		"f47ac10b-58cc-4372-c567-0e02b2c3d479",
+		"f47ac10b-58cc-4372-7567-0e02b2c3d479",
+		" f47ac10b-58cc-4372-a567-0e02b2c3d479",
+	}
+	for _, s := range invalid {
+		if IsUUID(s) || IsUUIDv4(s) {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestUUIDRule(t *testing.T) {
+	s := "f47ac10b-58cc-4372-a567-0e02b2c3d479"
+	if err := UUID()(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := UUID()(&s); err != nil {
+		t.Fatal(err)
+	}
+	if err := UUID()("x"); errors.Message(err) != `utils/validate: "x" is not a UUID` {
+		t.Fatal(err)
+	}
+	if UUID()(1) == nil {
+		t.Fatal()
+	}
+}
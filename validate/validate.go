@@ -0,0 +1,246 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+// Composable validators for values of any type.
+// Example: errs := validate.Validate(name, validate.Required(), validate.MaxLength(50))
+package validate
+
+import (
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/uestcer/utils/errors"
+)
+
+// A validator returning an error if v is invalid, otherwise nil.
+// Pointers are dereferenced by the built-in rules.
+type Rule func(v interface{}) error
+
+// Check v by every rule, and return the errors of the failed rules in order.
+// Return nil if v passes all rules.
+func Validate(v interface{}, rules ...Rule) []error {
+	var errs []error
+	for _, rule := range rules {
+		if err := rule(v); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// A rule passing if all rules pass, it returns the error of the first failed one.
+func All(rules ...Rule) Rule {
+	return func(v interface{}) error {
+		for _, rule := range rules {
+			if err := rule(v); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// A rule passing if any of rules passes, or if no rule is given.
+// Otherwise it returns an error joining the messages of all rules.
+func Any(rules ...Rule) Rule {
+	return func(v interface{}) error {
+		if len(rules) == 0 {
+			return nil
+		}
+
+		msgs := make([]string, 0, len(rules))
+		for _, rule := range rules {
+			err := rule(v)
+			if err == nil {
+				return nil
+			}
+			msgs = append(msgs, errors.Message(err))
+		}
+		return errors.New("utils/validate: no rule passed: " + strings.Join(msgs, "; "))
+	}
+}
+
+// A rule failing if v is nil, a nil pointer, the zero value, or an empty
+// string, slice or map.
+func Required() Rule {
+	return func(v interface{}) error {
+		if empty(v) {
+			return errors.New("utils/validate: value is required")
+		}
+		return nil
+	}
+}
+
+// A rule failing if the length of v is less than n. The length of a string
+// is its number of runes, v may also be a slice, array, map or chan.
+func MinLength(n int) Rule {
+	return func(v interface{}) error {
+		l, err := length(v)
+		if err != nil {
+			return err
+		}
+		if l < n {
+			return errors.Newf("utils/validate: length %d is less than %d", l, n)
+		}
+		return nil
+	}
+}
+
+// A rule failing if the length of v is greater than n, see MinLength.
+func MaxLength(n int) Rule {
+	return func(v interface{}) error {
+		l, err := length(v)
+		if err != nil {
+			return err
+		}
+		if l > n {
+			return errors.Newf("utils/validate: length %d is greater than %d", l, n)
+		}
+		return nil
+	}
+}
+
+// A rule failing if v is less than n, v must be an int, uint or float kind.
+func Min(n float64) Rule {
+	return func(v interface{}) error {
+		f, err := number(v)
+		if err != nil {
+			return err
+		}
+		if f < n {
+			return errors.Newf("utils/validate: %v is less than %v", f, n)
+		}
+		return nil
+	}
+}
+
+// A rule failing if v is greater than n, see Min.
+func Max(n float64) Rule {
+	return func(v interface{}) error {
+		f, err := number(v)
+		if err != nil {
+			return err
+		}
+		if f > n {
+			return errors.Newf("utils/validate: %v is greater than %v", f, n)
+		}
+		return nil
+	}
+}
+
+// A rule failing if v is not a string matching the regular expression pattern.
+// NOTE: Panic if pattern can not be compiled.
+func Matches(pattern string) Rule {
+	return matches(regexp.MustCompile(pattern))
+}
+
+// Same as Matches, with a compiled pattern.
+func matches(re *regexp.Regexp) Rule {
+	return func(v interface{}) error {
+		s, err := str(v)
+		if err != nil {
+			return err
+		}
+		if !re.MatchString(s) {
+			return errors.Newf("utils/validate: %q does not match %q", s, re.String())
+		}
+		return nil
+	}
+}
+
+// A rule failing if v is not a string of a bare email address, e.g. "a@b.com".
+func Email() Rule {
+	return func(v interface{}) error {
+		s, err := str(v)
+		if err != nil {
+			return err
+		}
+		if addr, err := mail.ParseAddress(s); err != nil || addr.Address != s {
+			return errors.Newf("utils/validate: %q is not an email address", s)
+		}
+		return nil
+	}
+}
+
+// A rule failing if v is not a string of an absolute URL with a host,
+// e.g. "https://example.com/path".
+func URL() Rule {
+	return func(v interface{}) error {
+		s, err := str(v)
+		if err != nil {
+			return err
+		}
+		if u, err := url.Parse(s); err != nil || u.Scheme == "" || u.Host == "" {
+			return errors.Newf("utils/validate: %q is not a URL", s)
+		}
+		return nil
+	}
+}
+
+// Dereference the pointers of v, return an invalid Value for a nil pointer.
+func indirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+// Check if v is nil, a nil pointer, the zero value, or an empty string,
+// slice or map, see Required.
+func empty(v interface{}) bool {
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || rv.IsZero() {
+		return true
+	}
+	rv = indirect(rv)
+	return !rv.IsValid() || rv.IsZero() || (hasLen(rv) && rv.Len() == 0)
+}
+
+// Check if v supports Len.
+func hasLen(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map, reflect.Chan:
+		return true
+	}
+	return false
+}
+
+func length(v interface{}) (int, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch {
+	case rv.Kind() == reflect.String:
+		return utf8.RuneCountInString(rv.String()), nil
+	case hasLen(rv):
+		return rv.Len(), nil
+	}
+	return 0, errors.Newf("utils/validate: value type %T has no length", v)
+}
+
+func number(v interface{}) (float64, error) {
+	rv := indirect(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), nil
+	}
+	return 0, errors.Newf("utils/validate: value type %T is not a number", v)
+}
+
+func str(v interface{}) (string, error) {
+	rv := indirect(reflect.ValueOf(v))
+	if rv.Kind() != reflect.String {
+		return "", errors.Newf("utils/validate: value type %T is not string", v)
+	}
+	return rv.String(), nil
+}
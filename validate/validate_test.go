@@ -0,0 +1,115 @@
+// Copyright 2014 li. All rights reserved.
+// Use of this source code is governed by a MIT/X11
+// license that can be found in the LICENSE file.
+
+package validate
+
+import (
+	"testing"
+
+	"github.com/uestcer/utils/errors"
+)
+
+func TestValidate(t *testing.T) {
+	if errs := Validate("gopher", Required(), MinLength(2), MaxLength(10)); errs != nil {
+		t.Fatal(errs)
+	}
+
+	errs := Validate("g", Required(), MinLength(2), MaxLength(0))
+	if len(errs) != 2 ||
+		errors.Message(errs[0]) != "utils/validate: length 1 is less than 2" ||
+		errors.Message(errs[1]) != "utils/validate: length 1 is greater than 0" {
+		t.Fatal(errs)
+	}
+}
+
+func TestRequired(t *testing.T) {
+	var nilPtr *int
+	zero := 0
+	for _, v := range []interface{}{nil, "", 0, nilPtr, []int{}, map[string]int{}, &zero} {
+		if Required()(v) == nil {
+			t.Fatalf("%#v", v)
+		}
+	}
+	one := 1
+	for _, v := range []interface{}{"a", 1, &one, []int{0}, true} {
+		if err := Required()(v); err != nil {
+			t.Fatal(v, err)
+		}
+	}
+}
+
+func TestLength(t *testing.T) {
+	if MinLength(3)("äöü") != nil || MaxLength(3)("äöü") != nil {
+		t.Fatal()
+	}
+	if MinLength(1)(map[int]int{1: 1}) != nil || MaxLength(1)([]int{1, 2}) == nil {
+		t.Fatal()
+	}
+	if err := MinLength(1)(42); errors.Message(err) != "utils/validate: value type int has no length" {
+		t.Fatal(err)
+	}
+}
+
+func TestMinMax(t *testing.T) {
+	if Min(1)(1) != nil || Min(1)(uint8(0)) == nil || Max(2.5)(2.5) != nil || Max(2.5)(3) == nil {
+		t.Fatal()
+	}
+	if err := Min(1)("1"); errors.Message(err) != "utils/validate: value type string is not a number" {
+		t.Fatal(err)
+	}
+	f := 1.5
+	if err := Max(1)(&f); errors.Message(err) != "utils/validate: 1.5 is greater than 1" {
+		t.Fatal(err)
+	}
+}
+
+func TestMatches(t *testing.T) {
+	rule := Matches(`^[a-z]+$`)
+	if rule("abc") != nil || rule("ABC") == nil || rule(1) == nil {
+		t.Fatal()
+	}
+}
+
+func TestEmailURL(t *testing.T) {
+	for _, s := range []string{"a@b.com", "first.last@example.org"} {
+		if err := Email()(s); err != nil {
+			t.Fatal(s, err)
+		}
+	}
+	for _, s := range []string{"", "a", "a@", "Bob <a@b.com>"} {
+		if Email()(s) == nil {
+			t.Fatal(s)
+		}
+	}
+
+	for _, s := range []string{"https://example.com", "http://localhost:8080/a?b=c"} {
+		if err := URL()(s); err != nil {
+			t.Fatal(s, err)
+		}
+	}
+	for _, s := range []string{"", "example.com", "/path", "http://"} {
+		if URL()(s) == nil {
+			t.Fatal(s)
+		}
+	}
+}
+
+func TestAllAny(t *testing.T) {
+	short := All(MinLength(1), MaxLength(3))
+	if short("ab") != nil || errors.Message(short("abcd")) != "utils/validate: length 4 is greater than 3" {
+		t.Fatal()
+	}
+
+	contact := Any(Email(), URL())
+	if contact("a@b.com") != nil || contact("https://b.com") != nil {
+		t.Fatal()
+	}
+	if err := contact("x"); errors.Message(err) !=
+		`utils/validate: no rule passed: utils/validate: "x" is not an email address; utils/validate: "x" is not a URL` {
+		t.Fatal(err)
+	}
+	if Any()("x") != nil || All()("x") != nil {
+		t.Fatal()
+	}
+}